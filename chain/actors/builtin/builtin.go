@@ -2,6 +2,7 @@
 package builtin
 
 import (
+	"reflect"
 	"strings"
 
 	"github.com/filecoin-project/go-address"
@@ -135,6 +136,194 @@ func ActorNameByCode(c cid.Cid) string {
 	}
 }
 
+// ActorCode describes the code of a builtin actor.
+type ActorCode struct {
+	Code    cid.Cid
+	Name    string // name of the code, such as fil/3/storageminer
+	Family  string // family of the actor, such as storageminer
+	Version int    // version of the actors the code belongs to
+}
+
+// AllActorCodes returns the codes of all builtin actors of every actors version.
+func AllActorCodes() []ActorCode {
+	var out []ActorCode
+	add := func(version int, codes ...cid.Cid) {
+		for _, c := range codes {
+			name := ActorNameByCode(c)
+			out = append(out, ActorCode{
+				Code:    c,
+				Name:    name,
+				Family:  ActorFamily(name),
+				Version: version,
+			})
+		}
+	}
+
+	add(0,
+		builtin0.SystemActorCodeID,
+		builtin0.InitActorCodeID,
+		builtin0.CronActorCodeID,
+		builtin0.AccountActorCodeID,
+		builtin0.StoragePowerActorCodeID,
+		builtin0.StorageMinerActorCodeID,
+		builtin0.StorageMarketActorCodeID,
+		builtin0.PaymentChannelActorCodeID,
+		builtin0.MultisigActorCodeID,
+		builtin0.RewardActorCodeID,
+		builtin0.VerifiedRegistryActorCodeID,
+	)
+
+	add(2,
+		builtin2.SystemActorCodeID,
+		builtin2.InitActorCodeID,
+		builtin2.CronActorCodeID,
+		builtin2.AccountActorCodeID,
+		builtin2.StoragePowerActorCodeID,
+		builtin2.StorageMinerActorCodeID,
+		builtin2.StorageMarketActorCodeID,
+		builtin2.PaymentChannelActorCodeID,
+		builtin2.MultisigActorCodeID,
+		builtin2.RewardActorCodeID,
+		builtin2.VerifiedRegistryActorCodeID,
+	)
+
+	add(3,
+		builtin3.SystemActorCodeID,
+		builtin3.InitActorCodeID,
+		builtin3.CronActorCodeID,
+		builtin3.AccountActorCodeID,
+		builtin3.StoragePowerActorCodeID,
+		builtin3.StorageMinerActorCodeID,
+		builtin3.StorageMarketActorCodeID,
+		builtin3.PaymentChannelActorCodeID,
+		builtin3.MultisigActorCodeID,
+		builtin3.RewardActorCodeID,
+		builtin3.VerifiedRegistryActorCodeID,
+	)
+
+	add(4,
+		builtin4.SystemActorCodeID,
+		builtin4.InitActorCodeID,
+		builtin4.CronActorCodeID,
+		builtin4.AccountActorCodeID,
+		builtin4.StoragePowerActorCodeID,
+		builtin4.StorageMinerActorCodeID,
+		builtin4.StorageMarketActorCodeID,
+		builtin4.PaymentChannelActorCodeID,
+		builtin4.MultisigActorCodeID,
+		builtin4.RewardActorCodeID,
+		builtin4.VerifiedRegistryActorCodeID,
+	)
+
+	add(5,
+		builtin5.SystemActorCodeID,
+		builtin5.InitActorCodeID,
+		builtin5.CronActorCodeID,
+		builtin5.AccountActorCodeID,
+		builtin5.StoragePowerActorCodeID,
+		builtin5.StorageMinerActorCodeID,
+		builtin5.StorageMarketActorCodeID,
+		builtin5.PaymentChannelActorCodeID,
+		builtin5.MultisigActorCodeID,
+		builtin5.RewardActorCodeID,
+		builtin5.VerifiedRegistryActorCodeID,
+	)
+	return out
+}
+
+// methodNames holds the names of the methods of each builtin actor code, indexed by method number.
+var methodNames = map[cid.Cid]map[abi.MethodNum]string{}
+
+func init() {
+	registerMethodNames(builtin0.SystemActorCodeID, struct{}{})
+	registerMethodNames(builtin0.InitActorCodeID, builtin0.MethodsInit)
+	registerMethodNames(builtin0.CronActorCodeID, builtin0.MethodsCron)
+	registerMethodNames(builtin0.AccountActorCodeID, builtin0.MethodsAccount)
+	registerMethodNames(builtin0.StoragePowerActorCodeID, builtin0.MethodsPower)
+	registerMethodNames(builtin0.StorageMinerActorCodeID, builtin0.MethodsMiner)
+	registerMethodNames(builtin0.StorageMarketActorCodeID, builtin0.MethodsMarket)
+	registerMethodNames(builtin0.PaymentChannelActorCodeID, builtin0.MethodsPaych)
+	registerMethodNames(builtin0.MultisigActorCodeID, builtin0.MethodsMultisig)
+	registerMethodNames(builtin0.RewardActorCodeID, builtin0.MethodsReward)
+	registerMethodNames(builtin0.VerifiedRegistryActorCodeID, builtin0.MethodsVerifiedRegistry)
+
+	registerMethodNames(builtin2.SystemActorCodeID, struct{}{})
+	registerMethodNames(builtin2.InitActorCodeID, builtin2.MethodsInit)
+	registerMethodNames(builtin2.CronActorCodeID, builtin2.MethodsCron)
+	registerMethodNames(builtin2.AccountActorCodeID, builtin2.MethodsAccount)
+	registerMethodNames(builtin2.StoragePowerActorCodeID, builtin2.MethodsPower)
+	registerMethodNames(builtin2.StorageMinerActorCodeID, builtin2.MethodsMiner)
+	registerMethodNames(builtin2.StorageMarketActorCodeID, builtin2.MethodsMarket)
+	registerMethodNames(builtin2.PaymentChannelActorCodeID, builtin2.MethodsPaych)
+	registerMethodNames(builtin2.MultisigActorCodeID, builtin2.MethodsMultisig)
+	registerMethodNames(builtin2.RewardActorCodeID, builtin2.MethodsReward)
+	registerMethodNames(builtin2.VerifiedRegistryActorCodeID, builtin2.MethodsVerifiedRegistry)
+
+	registerMethodNames(builtin3.SystemActorCodeID, struct{}{})
+	registerMethodNames(builtin3.InitActorCodeID, builtin3.MethodsInit)
+	registerMethodNames(builtin3.CronActorCodeID, builtin3.MethodsCron)
+	registerMethodNames(builtin3.AccountActorCodeID, builtin3.MethodsAccount)
+	registerMethodNames(builtin3.StoragePowerActorCodeID, builtin3.MethodsPower)
+	registerMethodNames(builtin3.StorageMinerActorCodeID, builtin3.MethodsMiner)
+	registerMethodNames(builtin3.StorageMarketActorCodeID, builtin3.MethodsMarket)
+	registerMethodNames(builtin3.PaymentChannelActorCodeID, builtin3.MethodsPaych)
+	registerMethodNames(builtin3.MultisigActorCodeID, builtin3.MethodsMultisig)
+	registerMethodNames(builtin3.RewardActorCodeID, builtin3.MethodsReward)
+	registerMethodNames(builtin3.VerifiedRegistryActorCodeID, builtin3.MethodsVerifiedRegistry)
+
+	registerMethodNames(builtin4.SystemActorCodeID, struct{}{})
+	registerMethodNames(builtin4.InitActorCodeID, builtin4.MethodsInit)
+	registerMethodNames(builtin4.CronActorCodeID, builtin4.MethodsCron)
+	registerMethodNames(builtin4.AccountActorCodeID, builtin4.MethodsAccount)
+	registerMethodNames(builtin4.StoragePowerActorCodeID, builtin4.MethodsPower)
+	registerMethodNames(builtin4.StorageMinerActorCodeID, builtin4.MethodsMiner)
+	registerMethodNames(builtin4.StorageMarketActorCodeID, builtin4.MethodsMarket)
+	registerMethodNames(builtin4.PaymentChannelActorCodeID, builtin4.MethodsPaych)
+	registerMethodNames(builtin4.MultisigActorCodeID, builtin4.MethodsMultisig)
+	registerMethodNames(builtin4.RewardActorCodeID, builtin4.MethodsReward)
+	registerMethodNames(builtin4.VerifiedRegistryActorCodeID, builtin4.MethodsVerifiedRegistry)
+
+	registerMethodNames(builtin5.SystemActorCodeID, struct{}{})
+	registerMethodNames(builtin5.InitActorCodeID, builtin5.MethodsInit)
+	registerMethodNames(builtin5.CronActorCodeID, builtin5.MethodsCron)
+	registerMethodNames(builtin5.AccountActorCodeID, builtin5.MethodsAccount)
+	registerMethodNames(builtin5.StoragePowerActorCodeID, builtin5.MethodsPower)
+	registerMethodNames(builtin5.StorageMinerActorCodeID, builtin5.MethodsMiner)
+	registerMethodNames(builtin5.StorageMarketActorCodeID, builtin5.MethodsMarket)
+	registerMethodNames(builtin5.PaymentChannelActorCodeID, builtin5.MethodsPaych)
+	registerMethodNames(builtin5.MultisigActorCodeID, builtin5.MethodsMultisig)
+	registerMethodNames(builtin5.RewardActorCodeID, builtin5.MethodsReward)
+	registerMethodNames(builtin5.VerifiedRegistryActorCodeID, builtin5.MethodsVerifiedRegistry)
+}
+
+func registerMethodNames(code cid.Cid, methods interface{}) {
+	names := map[abi.MethodNum]string{
+		methodSend: "Send",
+	}
+	v := reflect.ValueOf(methods)
+	for i := 0; i < v.NumField(); i++ {
+		names[abi.MethodNum(v.Field(i).Uint())] = v.Type().Field(i).Name
+	}
+	methodNames[code] = names
+}
+
+// methodSend is the method number of plain value transfers, which every actor accepts.
+const methodSend = abi.MethodNum(0)
+
+// MethodName returns the name of a method of the builtin actor with the given code, or an empty string when the method
+// is not known.
+func MethodName(code cid.Cid, method abi.MethodNum) string {
+	if method == methodSend {
+		return "Send"
+	}
+	return methodNames[code][method]
+}
+
+// MethodNames returns the names of the methods of the builtin actor with the given code, indexed by method number.
+func MethodNames(code cid.Cid) map[abi.MethodNum]string {
+	return methodNames[code]
+}
+
 func ActorFamily(name string) string {
 	if name == "<unknown>" {
 		return "<unknown>"
@@ -0,0 +1,68 @@
+package builtin
+
+import (
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/ipfs/go-cid"
+)
+
+// commonExitCodeNames holds the names of the exit codes that have the same meaning for every actor: the exit codes
+// set by the vm and the common exit codes of the builtin actors.
+var commonExitCodeNames = map[exitcode.ExitCode]string{
+	0:  "Ok",
+	1:  "SysErrSenderInvalid",
+	2:  "SysErrSenderStateInvalid",
+	3:  "SysErrInvalidMethod",
+	4:  "SysErrReserved1",
+	5:  "SysErrInvalidReceiver",
+	6:  "SysErrInsufficientFunds",
+	7:  "SysErrOutOfGas",
+	8:  "SysErrForbidden",
+	9:  "SysErrorIllegalActor",
+	10: "SysErrorIllegalArgument",
+	11: "SysErrReserved2",
+	12: "SysErrReserved3",
+	13: "SysErrReserved4",
+	14: "SysErrReserved5",
+	15: "SysErrReserved6",
+	16: "ErrIllegalArgument",
+	17: "ErrNotFound",
+	18: "ErrForbidden",
+	19: "ErrInsufficientFunds",
+	20: "ErrIllegalState",
+	21: "ErrSerialization",
+}
+
+// actorExitCodeNames holds the names of the exit codes specific to an actor family, which are the same in every
+// actors version.
+var actorExitCodeNames = map[string]map[exitcode.ExitCode]string{
+	"paymentchannel": {
+		exitcode.FirstActorSpecificExitCode: "ErrChannelStateUpdateAfterSettled",
+	},
+	"storageminer": {
+		1000: "ErrBalanceInvariantBroken",
+	},
+}
+
+// ExitCodeName returns the symbolic name of an exit code returned by the actor with the given code, or an empty string
+// when the exit code is not known. Exit codes common to all actors are named even if the actor code is undefined.
+func ExitCodeName(code cid.Cid, ec exitcode.ExitCode) string {
+	if name, ok := commonExitCodeNames[ec]; ok {
+		return name
+	}
+	if !code.Defined() {
+		return ""
+	}
+	return actorExitCodeNames[ActorFamily(ActorNameByCode(code))][ec]
+}
+
+// ExitCodeNames returns the names of all known exit codes that may be returned by the actor with the given code.
+func ExitCodeNames(code cid.Cid) map[exitcode.ExitCode]string {
+	out := make(map[exitcode.ExitCode]string, len(commonExitCodeNames))
+	for ec, name := range commonExitCodeNames {
+		out[ec] = name
+	}
+	for ec, name := range actorExitCodeNames[ActorFamily(ActorNameByCode(code))] {
+		out[ec] = name
+	}
+	return out
+}
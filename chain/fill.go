@@ -6,6 +6,7 @@ import (
 
 	"github.com/filecoin-project/lily/lens"
 	"github.com/filecoin-project/lily/model/visor"
+	"github.com/filecoin-project/lily/schedule"
 	"github.com/filecoin-project/lily/storage"
 	"github.com/go-pg/pg/v10"
 	"golang.org/x/xerrors"
@@ -17,6 +18,7 @@ type GapFiller struct {
 	name                 string
 	minHeight, maxHeight uint64
 	tasks                []string
+	progress             schedule.ProgressTracker
 }
 
 func NewGapFiller(node lens.API, db *storage.Database, name string, minHeight, maxHeight uint64, tasks []string) *GapFiller {
@@ -35,11 +37,17 @@ func (g *GapFiller) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	fillLog := log.With("type", "fill")
+	fillLog := schedule.Logger(ctx, &log.SugaredLogger).With("type", "fill")
 	fillLog.Infow("run", "count", len(gaps))
+	g.progress.Start(int64(len(heights)), true)
 
 	idx := 0
-	for _, height := range heights {
+	for i, height := range heights {
+		// yield to higher priority jobs
+		if err := schedule.WaitIfThrottled(ctx); err != nil {
+			return err
+		}
+
 		indexer, err := NewTipSetIndexer(g.node, g.DB, 0, g.name, gaps[height])
 		if err != nil {
 			return err
@@ -47,7 +55,9 @@ func (g *GapFiller) Run(ctx context.Context) error {
 
 		// walk a single height at a time since there is no guarantee neighboring heights share the same missing tasks.
 		if err := NewWalker(indexer, g.node, height, height).Run(ctx); err != nil {
-			log.Errorw("fill failed", "height", height, "error", err.Error())
+			fillLog.Errorw("fill failed", "height", height, "error", err.Error())
+			g.progress.Error(err)
+			g.progress.Processed(height, int64(len(heights)-i-1))
 			// TODO we could add an error to the gap report in a follow on if needed, but the actualy error should
 			// exist in the processing report if this fails.
 			continue
@@ -57,11 +67,17 @@ func (g *GapFiller) Run(ctx context.Context) error {
 		if err := g.setGapsFilled(ctx, height, gaps[height]...); err != nil {
 			return err
 		}
+		g.progress.Processed(height, int64(len(heights)-i-1))
 		idx += 1
 	}
 	return nil
 }
 
+// ReportProgress implements schedule.ProgressReporter.
+func (g *GapFiller) ReportProgress() *schedule.JobProgress {
+	return g.progress.ReportProgress()
+}
+
 // returns a map of heights to missing tasks, and a list of heights to iterate the map in order with.
 func (g *GapFiller) consolidateGaps(ctx context.Context) (map[int64][]string, []int64, error) {
 	gaps, err := g.queryGaps(ctx)
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"fmt"
 	"sync"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/filecoin-project/lily/chain/actors/adt"
 	"github.com/filecoin-project/lily/chain/actors/builtin/verifreg"
 	"github.com/filecoin-project/lily/tasks/consensus"
+	"github.com/filecoin-project/lily/tasks/consensusfaults"
 	"github.com/filecoin-project/lily/tasks/messageexecutions"
 	"github.com/filecoin-project/lotus/chain/state"
 	"github.com/filecoin-project/lotus/chain/types"
@@ -33,28 +35,46 @@ import (
 	"github.com/filecoin-project/lily/metrics"
 	"github.com/filecoin-project/lily/model"
 	visormodel "github.com/filecoin-project/lily/model/visor"
+	"github.com/filecoin-project/lily/schedule"
+	"github.com/filecoin-project/lily/storage"
+	"github.com/filecoin-project/lily/tasks/actorlifecycle"
 	"github.com/filecoin-project/lily/tasks/actorstate"
+	"github.com/filecoin-project/lily/tasks/blockrewards"
 	"github.com/filecoin-project/lily/tasks/blocks"
+	"github.com/filecoin-project/lily/tasks/calltree"
 	"github.com/filecoin-project/lily/tasks/chaineconomics"
+	"github.com/filecoin-project/lily/tasks/epochsummary"
+	"github.com/filecoin-project/lily/tasks/gastrace"
 	"github.com/filecoin-project/lily/tasks/messages"
 	"github.com/filecoin-project/lily/tasks/msapprovals"
+	"github.com/filecoin-project/lily/tasks/networkversion"
+	"github.com/filecoin-project/lily/tasks/nonces"
 )
 
 const (
-	ActorStatesRawTask      = "actorstatesraw"      // task that only extracts raw actor state
-	ActorStatesPowerTask    = "actorstatespower"    // task that only extracts power actor states (but not the raw state)
-	ActorStatesRewardTask   = "actorstatesreward"   // task that only extracts reward actor states (but not the raw state)
-	ActorStatesMinerTask    = "actorstatesminer"    // task that only extracts miner actor states (but not the raw state)
-	ActorStatesInitTask     = "actorstatesinit"     // task that only extracts init actor states (but not the raw state)
-	ActorStatesMarketTask   = "actorstatesmarket"   // task that only extracts market actor states (but not the raw state)
-	ActorStatesMultisigTask = "actorstatesmultisig" // task that only extracts multisig actor states (but not the raw state)
-	ActorStatesVerifreg     = "actorstatesverifreg" // task that only extracts verified registry actor states (but not the raw state)
-	BlocksTask              = "blocks"              // task that extracts block data
-	MessagesTask            = "messages"            // task that extracts message data
-	ChainEconomicsTask      = "chaineconomics"      // task that extracts chain economics data
-	MultisigApprovalsTask   = "msapprovals"         // task that extracts multisig actor approvals
-	ImplicitMessageTask     = "implicitmessage"     // task that extract implicitly executed messages: cron tick and block reward.
-	ChainConsensusTask      = "consensus"
+	ActorStatesRawTask        = "actorstatesraw"      // task that only extracts raw actor state
+	ActorStatesPowerTask      = "actorstatespower"    // task that only extracts power actor states (but not the raw state)
+	ActorStatesRewardTask     = "actorstatesreward"   // task that only extracts reward actor states (but not the raw state)
+	ActorStatesMinerTask      = "actorstatesminer"    // task that only extracts miner actor states (but not the raw state)
+	ActorStatesInitTask       = "actorstatesinit"     // task that only extracts init actor states (but not the raw state)
+	ActorStatesMarketTask     = "actorstatesmarket"   // task that only extracts market actor states (but not the raw state)
+	ActorStatesMultisigTask   = "actorstatesmultisig" // task that only extracts multisig actor states (but not the raw state)
+	ActorStatesVerifreg       = "actorstatesverifreg" // task that only extracts verified registry actor states (but not the raw state)
+	BlocksTask                = "blocks"              // task that extracts block data
+	MessagesTask              = "messages"            // task that extracts message data
+	ChainEconomicsTask        = "chaineconomics"      // task that extracts chain economics data
+	MultisigApprovalsTask     = "msapprovals"         // task that extracts multisig actor approvals
+	ImplicitMessageTask       = "implicitmessage"     // task that extract implicitly executed messages: cron tick and block reward.
+	ChainConsensusTask        = "consensus"
+	ConsensusFaultsTask       = "consensusfaults"       // task that extracts consensus faults reported to miner actors
+	BlockRewardsTask          = "blockrewards"          // task that attributes block rewards to the blocks that won them
+	MinerSectorLifecyclesTask = "minersectorlifecycles" // task that maintains the lifecycle of each sector from miner sector events
+	GasTraceTask              = "gastrace"              // task that extracts gas charges from message execution traces, not run by default
+	CallTreeTask              = "calltree"              // task that extracts the tree of calls made by each executed message
+	EpochSummaryTask          = "epochsummary"          // task that summarizes the blocks and message execution of each epoch
+	ActorLifecycleTask        = "actorlifecycle"        // task that records the creation, deletion and upgrade of actors
+	NetworkVersionTask        = "networkversion"        // task that records changes to the network, actors and state tree versions
+	MessageNoncesTask         = "messagenonces"         // task that records the nonces of each sender and messages replaced on reverted tipsets
 )
 
 var AllTasks = []string{
@@ -71,6 +91,19 @@ var AllTasks = []string{
 	MultisigApprovalsTask,
 	ImplicitMessageTask,
 	ChainConsensusTask,
+	ConsensusFaultsTask,
+	BlockRewardsTask,
+	MinerSectorLifecyclesTask,
+	CallTreeTask,
+	EpochSummaryTask,
+	ActorLifecycleTask,
+	NetworkVersionTask,
+	MessageNoncesTask,
+}
+
+// KnownTasks returns the names of all tasks that may be run by an indexer, including those that are not run by default.
+func KnownTasks() []string {
+	return append([]string{ActorStatesVerifreg, GasTraceTask}, AllTasks...)
 }
 
 var log = logging.Logger("lily/chain")
@@ -90,10 +123,19 @@ type TipSetIndexer struct {
 	persistSlot                chan struct{} // filled with a token when a goroutine is persisting data
 	lastTipSet                 *types.TipSet
 	node                       lens.API
+	gasTraceLevel              gastrace.Level
+	deriveSectorLifecycles     bool // sector lifecycles are derived from the output of the miner actor state task
 }
 
 type TipSetIndexerOpt func(t *TipSetIndexer)
 
+// GasTraceLevel sets the level of detail recorded by the gastrace task.
+func GasTraceLevel(level gastrace.Level) TipSetIndexerOpt {
+	return func(t *TipSetIndexer) {
+		t.gasTraceLevel = level
+	}
+}
+
 // NewTipSetIndexer extracts block, message and actor state data from a tipset and persists it to storage. Extraction
 // and persistence are concurrent. Extraction of the a tipset can proceed while data from the previous extraction is
 // being persisted. The indexer may be given a time window in which to complete data extraction. The name of the
@@ -110,6 +152,11 @@ func NewTipSetIndexer(node lens.API, d model.Storage, window time.Duration, name
 		consensusProcessor:         map[string]TipSetsProcessor{},
 		actorProcessors:            map[string]ActorProcessor{},
 		node:                       node,
+		gasTraceLevel:              gastrace.LevelMessage,
+	}
+
+	for _, opt := range options {
+		opt(tsi)
 	}
 
 	for _, task := range tasks {
@@ -117,9 +164,11 @@ func NewTipSetIndexer(node lens.API, d model.Storage, window time.Duration, name
 		case BlocksTask:
 			tsi.processors[BlocksTask] = blocks.NewTask()
 		case MessagesTask:
-			tsi.messageProcessors[MessagesTask] = messages.NewTask()
+			tsi.messageProcessors[MessagesTask] = messages.NewTask(node)
 		case ChainEconomicsTask:
 			tsi.processors[ChainEconomicsTask] = chaineconomics.NewTask(node)
+		case NetworkVersionTask:
+			tsi.processors[NetworkVersionTask] = networkversion.NewTask(node)
 		case ActorStatesRawTask:
 			tsi.actorProcessors[ActorStatesRawTask] = actorstate.NewTask(node, &actorstate.RawActorExtractorMap{})
 		case ActorStatesPowerTask:
@@ -136,19 +185,40 @@ func NewTipSetIndexer(node lens.API, d model.Storage, window time.Duration, name
 			tsi.actorProcessors[ActorStatesMultisigTask] = actorstate.NewTask(node, actorstate.NewTypedActorExtractorMap(multisig.AllCodes()))
 		case ActorStatesVerifreg:
 			tsi.actorProcessors[ActorStatesVerifreg] = actorstate.NewTask(node, actorstate.NewTypedActorExtractorMap(verifreg.AllCodes()))
+		case MinerSectorLifecyclesTask:
+			tsi.actorProcessors[MinerSectorLifecyclesTask] = actorstate.NewTask(node, &actorstate.MinerSectorLifecycleExtractorMap{})
 		case MultisigApprovalsTask:
 			tsi.messageProcessors[MultisigApprovalsTask] = msapprovals.NewTask(node)
 		case ChainConsensusTask:
 			tsi.consensusProcessor[ChainConsensusTask] = consensus.NewTask()
 		case ImplicitMessageTask:
 			tsi.messageExecutionProcessors[ImplicitMessageTask] = messageexecutions.NewTask()
+		case ConsensusFaultsTask:
+			tsi.messageExecutionProcessors[ConsensusFaultsTask] = consensusfaults.NewTask()
+		case BlockRewardsTask:
+			tsi.messageExecutionProcessors[BlockRewardsTask] = blockrewards.NewTask()
+		case ActorLifecycleTask:
+			tsi.actorProcessors[ActorLifecycleTask] = actorlifecycle.NewTask(node)
+		case MessageNoncesTask:
+			tsi.messageProcessors[MessageNoncesTask] = nonces.NewTask(node)
+		case EpochSummaryTask:
+			tsi.messageProcessors[EpochSummaryTask] = epochsummary.NewTask()
+		case CallTreeTask:
+			tsi.messageExecutionProcessors[CallTreeTask] = calltree.NewTask()
+		case GasTraceTask:
+			tsi.messageExecutionProcessors[GasTraceTask] = gastrace.NewTask(tsi.gasTraceLevel)
 		default:
 			return nil, xerrors.Errorf("unknown task: %s", task)
 		}
 	}
 
-	for _, opt := range options {
-		opt(tsi)
+	// Sector lifecycles are derived from the sector events extracted by the miner task when it is running rather than
+	// extracting the sector changes of each miner a second time.
+	if _, ok := tsi.actorProcessors[ActorStatesMinerTask]; ok {
+		if _, ok := tsi.actorProcessors[MinerSectorLifecyclesTask]; ok {
+			delete(tsi.actorProcessors, MinerSectorLifecyclesTask)
+			tsi.deriveSectorLifecycles = true
+		}
 	}
 
 	return tsi, nil
@@ -209,7 +279,7 @@ func (t *TipSetIndexer) TipSet(ctx context.Context, ts *types.TipSet) error {
 		return nil
 	}
 
-	ll := log.With("current", int64(current.Height()), "next", int64(next.Height()))
+	ll := schedule.Logger(ctx, &log.SugaredLogger).With("current", int64(current.Height()), "next", int64(next.Height()))
 	ll.Debugw("indexing tipset")
 
 	// Run each tipset processing task concurrently
@@ -261,7 +331,7 @@ func (t *TipSetIndexer) TipSet(ctx context.Context, ts *types.TipSet) error {
 						ll.Errorw("failed to extract actor changes", "error", err)
 						terr := xerrors.Errorf("failed to extract actor changes: %w", err)
 						// We need to report that all actor tasks failed
-						for name := range t.actorProcessors {
+						for _, name := range t.actorTaskNames() {
 							report := &visormodel.ProcessingReport{
 								Height:         int64(current.Height()),
 								StateRoot:      current.ParentState().String(),
@@ -295,7 +365,7 @@ func (t *TipSetIndexer) TipSet(ctx context.Context, ts *types.TipSet) error {
 					taskOutputs[name] = model.PersistableList{report}
 				}
 				// We also need to report that all actor tasks failed
-				for name := range t.actorProcessors {
+				for _, name := range t.actorTaskNames() {
 					report := &visormodel.ProcessingReport{
 						Height:         int64(current.Height()),
 						StateRoot:      current.ParentState().String(),
@@ -355,12 +425,23 @@ func (t *TipSetIndexer) TipSet(ctx context.Context, ts *types.TipSet) error {
 			taskOutputs[name] = model.PersistableList{t.buildSkippedTipsetReport(ts, name, start, reason)}
 			ll.Infow("task skipped", "task", name, "reason", reason)
 		}
-		for name := range t.actorProcessors {
+		for _, name := range t.actorTaskNames() {
 			taskOutputs[name] = model.PersistableList{t.buildSkippedTipsetReport(ts, name, start, reason)}
 			ll.Infow("task skipped", "task", name, "reason", reason)
 		}
 	}
 
+	// errors reported by tasks or encountered while persisting, recorded as the outcome of processing the tipset
+	var outcomeErrs []string
+	recordOutcome := func() {
+		schedule.RecordOutcome(ctx, schedule.Outcome{
+			Height:   int64(current.Height()),
+			At:       time.Now().UTC(),
+			Duration: time.Since(start),
+			Errors:   outcomeErrs,
+		})
+	}
+
 	// Wait for all tasks to complete
 	for inFlight > 0 {
 		var res *TaskResult
@@ -376,6 +457,8 @@ func (t *TipSetIndexer) TipSet(ctx context.Context, ts *types.TipSet) error {
 		// Was there a fatal error?
 		if res.Error != nil {
 			llt.Errorw("task returned with error", "error", res.Error.Error())
+			outcomeErrs = append(outcomeErrs, fmt.Sprintf("%s: %v", res.Task, res.Error))
+			recordOutcome()
 			// tell all the processors to close their connections to the lens, they can reopen when needed
 			return res.Error
 		}
@@ -395,6 +478,7 @@ func (t *TipSetIndexer) TipSet(ctx context.Context, ts *types.TipSet) error {
 
 			if res.Report[idx].ErrorsDetected != nil {
 				res.Report[idx].Status = visormodel.ProcessingStatusError
+				outcomeErrs = append(outcomeErrs, fmt.Sprintf("%s: %v", res.Task, res.Report[idx].ErrorsDetected))
 			} else if res.Report[idx].StatusInformation != "" {
 				res.Report[idx].Status = visormodel.ProcessingStatusInfo
 			} else {
@@ -406,12 +490,23 @@ func (t *TipSetIndexer) TipSet(ctx context.Context, ts *types.TipSet) error {
 
 		// Persist the processing report and the data in a single transaction
 		taskOutputs[res.Task] = model.PersistableList{res.Report, res.Data}
+
+		if res.Task == ActorStatesMinerTask && t.deriveSectorLifecycles {
+			report := make(visormodel.ProcessingReportList, len(res.Report))
+			for idx := range res.Report {
+				r := *res.Report[idx]
+				r.Task = MinerSectorLifecyclesTask
+				report[idx] = &r
+			}
+			taskOutputs[MinerSectorLifecyclesTask] = model.PersistableList{report, actorstate.MinerSectorLifecyclesFromResults(res.Data)}
+		}
 	}
 	ll.Debugw("data extracted", "time", time.Since(start))
 
 	if len(taskOutputs) == 0 {
 		// Nothing to persist
 		ll.Infow("tasks complete, nothing to persist", "total_time", time.Since(start))
+		recordOutcome()
 		return nil
 	}
 
@@ -423,6 +518,9 @@ func (t *TipSetIndexer) TipSet(ctx context.Context, ts *types.TipSet) error {
 		// Slot was free so we can continue. Slot is now taken.
 	}
 
+	// Rows are timestamped from the tipsets they were extracted from when storage supports it
+	ctx = storage.WithTipSetTimestamps(ctx, current, next)
+
 	// Persist all results
 	go func() {
 		// free up the slot when done
@@ -432,6 +530,7 @@ func (t *TipSetIndexer) TipSet(ctx context.Context, ts *types.TipSet) error {
 
 		ll.Debugw("persisting data", "time", time.Since(start))
 		var wg sync.WaitGroup
+		var errMu sync.Mutex
 		wg.Add(len(taskOutputs))
 
 		// Persist each processor's data concurrently since they don't overlap
@@ -444,6 +543,9 @@ func (t *TipSetIndexer) TipSet(ctx context.Context, ts *types.TipSet) error {
 				if err := t.storage.PersistBatch(ctx, p); err != nil {
 					stats.Record(ctx, metrics.PersistFailure.M(1))
 					ll.Errorw("persistence failed", "task", task, "error", err)
+					errMu.Lock()
+					outcomeErrs = append(outcomeErrs, fmt.Sprintf("%s: persist: %v", task, err))
+					errMu.Unlock()
 					return
 				}
 				ll.Debugw("task data persisted", "task", task, "time", time.Since(start))
@@ -451,11 +553,25 @@ func (t *TipSetIndexer) TipSet(ctx context.Context, ts *types.TipSet) error {
 		}
 		wg.Wait()
 		ll.Infow("tasks complete", "total_time", time.Since(start))
+		recordOutcome()
 	}()
 
 	return nil
 }
 
+// actorTaskNames returns the names of the tasks that depend on actor state changes, including those derived from the
+// output of another task.
+func (t *TipSetIndexer) actorTaskNames() []string {
+	names := make([]string, 0, len(t.actorProcessors)+1)
+	for name := range t.actorProcessors {
+		names = append(names, name)
+	}
+	if t.deriveSectorLifecycles {
+		names = append(names, MinerSectorLifecyclesTask)
+	}
+	return names
+}
+
 func (t *TipSetIndexer) runProcessor(ctx context.Context, p TipSetProcessor, name string, ts *types.TipSet, results chan *TaskResult) {
 	ctx, _ = tag.New(ctx, tag.Upsert(metrics.TaskType, name))
 	stats.Record(ctx, metrics.TipsetHeight.M(int64(ts.Height())))
@@ -581,6 +697,14 @@ func (t *TipSetIndexer) stateChangedActors(ctx context.Context, old, new cid.Cid
 					if err != nil {
 						return nil, err
 					}
+					var prev types.Actor
+					buf.Reset(change.Before.Raw)
+					err = prev.UnmarshalCBOR(buf)
+					buf.Reset(nil)
+					if err != nil {
+						return nil, err
+					}
+					ch.PrevCode = prev.Code
 				}
 				out[addr.String()] = ch
 			}
@@ -593,11 +717,68 @@ func (t *TipSetIndexer) stateChangedActors(ctx context.Context, old, new cid.Cid
 		return nil, err
 	}
 
-	for addr, act := range actors {
-		out[addr] = lens.ActorStateChange{
+	// StateChangedActors does not say how each actor changed. Resolving that requires scanning the whole of the old
+	// state tree so it is only done when the actor lifecycle task, which depends on it, is running.
+	if _, ok := t.actorProcessors[ActorLifecycleTask]; !ok {
+		for addr, act := range actors {
+			out[addr] = lens.ActorStateChange{
+				Actor:      act,
+				ChangeType: lens.ChangeTypeUnknown,
+			}
+		}
+		return out, nil
+	}
+
+	oldTree, err := state.LoadStateTree(t.node.Store(), old)
+	if err != nil {
+		return nil, xerrors.Errorf("load old state tree: %w", err)
+	}
+	newTree, err := state.LoadStateTree(t.node.Store(), new)
+	if err != nil {
+		return nil, xerrors.Errorf("load new state tree: %w", err)
+	}
+
+	for addrStr, act := range actors {
+		addr, err := address.NewFromString(addrStr)
+		if err != nil {
+			return nil, xerrors.Errorf("address in state tree was not valid: %w", err)
+		}
+		ch := lens.ActorStateChange{
 			Actor:      act,
-			ChangeType: lens.ChangeTypeUnknown,
+			ChangeType: lens.ChangeTypeModify,
 		}
+		prev, err := oldTree.GetActor(addr)
+		if err != nil {
+			if !xerrors.Is(err, types.ErrActorNotFound) {
+				return nil, xerrors.Errorf("get actor %s from old state tree: %w", addr, err)
+			}
+			ch.ChangeType = lens.ChangeTypeAdd
+		} else {
+			ch.PrevCode = prev.Code
+		}
+		out[addrStr] = ch
+	}
+
+	// StateChangedActors only reports actors that are present in the new state tree, so removed actors are found
+	// by looking for the actors of the old state tree in the new one.
+	if err := oldTree.ForEach(func(addr address.Address, act *types.Actor) error {
+		if _, ok := out[addr.String()]; ok {
+			return nil
+		}
+		_, err := newTree.GetActor(addr)
+		if err == nil {
+			return nil
+		}
+		if !xerrors.Is(err, types.ErrActorNotFound) {
+			return xerrors.Errorf("get actor %s from new state tree: %w", addr, err)
+		}
+		out[addr.String()] = lens.ActorStateChange{
+			Actor:      *act,
+			ChangeType: lens.ChangeTypeRemove,
+		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
 	return out, nil
@@ -744,11 +925,11 @@ func (t *TipSetIndexer) SkipTipSet(ctx context.Context, ts *types.TipSet, reason
 		reports = append(reports, t.buildSkippedTipsetReport(ts, name, timestamp, reason))
 	}
 
-	for name := range t.actorProcessors {
+	for _, name := range t.actorTaskNames() {
 		reports = append(reports, t.buildSkippedTipsetReport(ts, name, timestamp, reason))
 	}
 
-	if err := t.storage.PersistBatch(ctx, reports...); err != nil {
+	if err := t.storage.PersistBatch(storage.WithTipSetTimestamps(ctx, ts), reports...); err != nil {
 		return xerrors.Errorf("persist reports: %w", err)
 	}
 	return nil
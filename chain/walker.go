@@ -2,6 +2,7 @@ package chain
 
 import (
 	"context"
+	"sync"
 
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/lotus/chain/types"
@@ -11,6 +12,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/schedule"
 )
 
 func NewWalker(obs TipSetObserver, node lens.API, minHeight, maxHeight int64) *Walker {
@@ -28,6 +30,46 @@ type Walker struct {
 	obs       TipSetObserver
 	minHeight int64 // limit persisting to tipsets equal to or above this height
 	maxHeight int64 // limit persisting to tipsets equal to or below this height}
+
+	mu       sync.Mutex // protects following fields
+	lowest   int64      // lowest height notified to the observer
+	notified bool       // true if any tipset has been notified to the observer
+
+	progress schedule.ProgressTracker
+}
+
+// ReportProgress implements schedule.ProgressReporter.
+func (c *Walker) ReportProgress() *schedule.JobProgress {
+	return c.progress.ReportProgress()
+}
+
+// Progress returns the lowest height that has been notified to the observer. Since the walker moves from maxHeight
+// towards minHeight, a walk may be resumed by walking from this height.
+func (c *Walker) Progress() (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lowest, c.notified
+}
+
+func (c *Walker) notify(ctx context.Context, ts *types.TipSet) error {
+	log.Debugw("found tipset", "height", ts.Height())
+	if err := c.obs.TipSet(ctx, ts); err != nil {
+		c.progress.Error(err)
+		return xerrors.Errorf("notify tipset: %w", err)
+	}
+
+	// the tipset above maxHeight is only notified to provide a parent for the tipset at maxHeight
+	if int64(ts.Height()) <= c.maxHeight {
+		c.progress.Processed(int64(ts.Height()), int64(ts.Height())-c.minHeight)
+	}
+
+	c.mu.Lock()
+	if !c.notified || int64(ts.Height()) < c.lowest {
+		c.lowest = int64(ts.Height())
+		c.notified = true
+	}
+	c.mu.Unlock()
+	return nil
 }
 
 // Run starts walking the chain history and continues until the context is done or
@@ -57,6 +99,7 @@ func (c *Walker) Run(ctx context.Context) error {
 		}
 	}
 
+	c.progress.Start(c.maxHeight-c.minHeight+1, true)
 	if err := c.WalkChain(ctx, c.node, ts); err != nil {
 		return xerrors.Errorf("walk chain: %w", err)
 	}
@@ -68,9 +111,8 @@ func (c *Walker) WalkChain(ctx context.Context, node lens.API, ts *types.TipSet)
 	ctx, span := global.Tracer("").Start(ctx, "Walker.WalkChain", trace.WithAttributes(label.Int64("height", c.maxHeight)))
 	defer span.End()
 
-	log.Debugw("found tipset", "height", ts.Height())
-	if err := c.obs.TipSet(ctx, ts); err != nil {
-		return xerrors.Errorf("notify tipset: %w", err)
+	if err := c.notify(ctx, ts); err != nil {
+		return err
 	}
 
 	var err error
@@ -81,6 +123,11 @@ func (c *Walker) WalkChain(ctx context.Context, node lens.API, ts *types.TipSet)
 		default:
 		}
 
+		// yield to higher priority jobs
+		if err := schedule.WaitIfThrottled(ctx); err != nil {
+			return err
+		}
+
 		ts, err = node.ChainGetTipSet(ctx, ts.Parents())
 		if err != nil {
 			return xerrors.Errorf("get tipset: %w", err)
@@ -90,9 +137,8 @@ func (c *Walker) WalkChain(ctx context.Context, node lens.API, ts *types.TipSet)
 			break
 		}
 
-		log.Debugw("found tipset", "height", ts.Height())
-		if err := c.obs.TipSet(ctx, ts); err != nil {
-			return xerrors.Errorf("notify tipset: %w", err)
+		if err := c.notify(ctx, ts); err != nil {
+			return err
 		}
 
 	}
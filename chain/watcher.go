@@ -3,12 +3,14 @@ package chain
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 
 	"github.com/filecoin-project/lotus/chain/types"
 	"go.opencensus.io/stats"
 	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/schedule"
 )
 
 // NewWatcher creates a new Watcher. confidence sets the number of tipsets that will be held
@@ -31,11 +33,54 @@ type Watcher struct {
 	confidence int           // size of tipset cache
 	cache      *TipSetCache  // caches tipsets for possible reversion
 	indexSlot  chan struct{} // filled with a token when a goroutine is indexing a tipset
+	headHeight int64         // height of the last head event, accessed atomically
+	indexedAt  int64         // height of the last tipset indexed, accessed atomically
+	applied    int64         // number of tipsets applied to the cache, accessed atomically
+	indexedSeq int64         // value of applied when the last tipset indexed was evicted from the cache, accessed atomically
+	skipped    int32         // 1 if a tipset has been skipped since the last tipset was indexed, accessed atomically
+	progress   schedule.ProgressTracker
+}
+
+// ReportProgress implements schedule.ProgressReporter. Remaining heights are those between the head of the chain and
+// the last tipset indexed, including those held in the tipset cache.
+func (c *Watcher) ReportProgress() *schedule.JobProgress {
+	return c.progress.ReportProgress()
+}
+
+// lagTolerance is the number of tipsets beyond the confidence window that the watcher may fall behind the head of the
+// chain before it reports that it is lagging.
+const lagTolerance = 2
+
+// Lagging implements schedule.LagReporter. The watcher is lagging when it has skipped a tipset since it last indexed
+// one or when the last tipset indexed is further behind the head than the confidence window allows. Lag is counted
+// in tipsets, like the confidence window, so null rounds do not count towards it.
+func (c *Watcher) Lagging() bool {
+	if atomic.LoadInt32(&c.skipped) == 1 {
+		return true
+	}
+	indexed := atomic.LoadInt64(&c.indexedAt)
+	if indexed == 0 {
+		// nothing indexed yet
+		return false
+	}
+	// tipsets applied since the last tipset indexed was evicted from the cache, which was then confidence tipsets
+	// behind the head
+	return atomic.LoadInt64(&c.applied)-atomic.LoadInt64(&c.indexedSeq) > lagTolerance
+}
+
+// indexed records that a tipset has been notified to the observer. seq is the number of tipsets that had been applied
+// when the tipset was evicted from the cache.
+func (c *Watcher) indexed(ts *types.TipSet, seq int64) {
+	atomic.StoreInt64(&c.indexedAt, int64(ts.Height()))
+	atomic.StoreInt64(&c.indexedSeq, seq)
+	atomic.StoreInt32(&c.skipped, 0)
+	c.progress.Processed(int64(ts.Height()), atomic.LoadInt64(&c.headHeight)-int64(ts.Height()))
 }
 
 // Run starts following the chain head and blocks until the context is done or
 // an error occurs.
 func (c *Watcher) Run(ctx context.Context) error {
+	c.progress.Start(0, false)
 	for {
 		select {
 		case <-ctx.Done():
@@ -46,6 +91,7 @@ func (c *Watcher) Run(ctx context.Context) error {
 			}
 			if he != nil && he.TipSet != nil {
 				metrics.RecordCount(ctx, metrics.WatchHeight, int(he.TipSet.Height()))
+				atomic.StoreInt64(&c.headHeight, int64(he.TipSet.Height()))
 			}
 
 			if err := c.index(ctx, he); err != nil {
@@ -66,10 +112,13 @@ func (c *Watcher) index(ctx context.Context, he *HeadEvent) error {
 		// If we have a zero confidence window then we need to notify every tipset we see
 		if c.confidence == 0 {
 			if err := c.obs.TipSet(ctx, he.TipSet); err != nil {
+				c.progress.Error(err)
 				return xerrors.Errorf("notify tipset: %w", err)
 			}
+			c.indexed(he.TipSet, atomic.LoadInt64(&c.applied))
 		}
 	case HeadEventApply:
+		atomic.AddInt64(&c.applied, 1)
 		tail, err := c.cache.Add(he.TipSet)
 		if err != nil {
 			log.Errorw("tipset cache add", "error", err.Error())
@@ -110,6 +159,7 @@ func (c *Watcher) maybeIndexTipSet(ctx context.Context, ts *types.TipSet) error
 		return ctx.Err()
 	case c.indexSlot <- struct{}{}:
 		// Indexing slot was available which means we can continue.
+		seq := atomic.LoadInt64(&c.applied)
 		go func() {
 			// Clear the slot when we have completed indexing
 			defer func() {
@@ -117,17 +167,22 @@ func (c *Watcher) maybeIndexTipSet(ctx context.Context, ts *types.TipSet) error
 			}()
 
 			if err := c.obs.TipSet(ctx, ts); err != nil {
-				log.Errorw("failed to index tipset", "error", err, "height", ts.Height())
+				schedule.Logger(ctx, &log.SugaredLogger).Errorw("failed to index tipset", "error", err, "height", ts.Height())
+				c.progress.Error(err)
+				return
 			}
+			c.indexed(ts, seq)
 		}()
 	default:
 		// The indexer is taking longer than one epoch to process. We need to avoid blocking the stream of incoming
 		// tipsets otherwise we will cause the node to fall behind the chain while it waits for us to catch up
 		// (which may never happen if we consistently take too long)
-		log.Errorw("skipping tipset since indexer is not ready", "height", ts.Height())
+		schedule.Logger(ctx, &log.SugaredLogger).Errorw("skipping tipset since indexer is not ready", "height", ts.Height())
 		stats.Record(ctx, metrics.TipSetSkip.M(1))
+		c.progress.Error(xerrors.Errorf("skipped tipset at height %d: indexer not ready", ts.Height()))
+		atomic.StoreInt32(&c.skipped, 1)
 		if err := c.obs.SkipTipSet(ctx, ts, "indexer not ready"); err != nil {
-			log.Errorw("failed to skip tipset", "error", err, "height", ts.Height())
+			schedule.Logger(ctx, &log.SugaredLogger).Errorw("failed to skip tipset", "error", err, "height", ts.Height())
 		}
 	}
 
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/filecoin-project/go-jsonrpc/auth"
+	lotuscli "github.com/filecoin-project/lotus/cli"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/commands/util"
+)
+
+var authFlags struct {
+	perm string
+}
+
+var AuthCmd = &cli.Command{
+	Name:  "auth",
+	Usage: "Manage API permissions.",
+	Subcommands: []*cli.Command{
+		AuthCreateTokenCmd,
+	},
+}
+
+var AuthCreateTokenCmd = &cli.Command{
+	Name:  "create-token",
+	Usage: "create a token for the lily api",
+	Description: `Creates a token granting a permission level on the daemon's API. Levels are
+ordered and each grants the levels before it:
+
+  read    query the chain, list jobs and show their progress, logs and outcomes
+  job     start, stop, forget and remove existing jobs
+  write   create watch, walk, gap and index jobs and change log levels
+  admin   create tokens and shut down the daemon
+
+Creating a token requires an admin token. When --api-token is not given the
+token written to the repository by the daemon is used.`,
+	Flags: flagSet(
+		clientAPIFlagSet,
+		[]cli.Flag{
+			&cli.StringFlag{
+				Name:        "perm",
+				Usage:       "Permission level to grant, one of " + permNames(),
+				Required:    true,
+				Destination: &authFlags.perm,
+			},
+		},
+	),
+	Action: func(cctx *cli.Context) error {
+		var perms []auth.Permission
+		for i, p := range util.AllPermissions {
+			if p == auth.Permission(authFlags.perm) {
+				perms = util.AllPermissions[:i+1]
+				break
+			}
+		}
+		if perms == nil {
+			return xerrors.Errorf("unknown permission %q, must be one of %s", authFlags.perm, permNames())
+		}
+
+		ctx := lotuscli.ReqContext(cctx)
+		api, closer, err := GetAPI(ctx, clientAPIFlags.apiAddr, clientAPIFlags.apiToken)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		token, err := api.AuthNew(ctx, perms)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(os.Stdout, string(token)); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+func permNames() string {
+	names := make([]string, 0, len(util.AllPermissions))
+	for _, p := range util.AllPermissions {
+		names = append(names, string(p))
+	}
+	return strings.Join(names, ", ")
+}
@@ -9,6 +9,7 @@ import (
 	lotusbuild "github.com/filecoin-project/lotus/build"
 	"github.com/filecoin-project/lotus/chain/events"
 	"github.com/filecoin-project/lotus/chain/stmgr"
+	"github.com/filecoin-project/lotus/chain/vm"
 	lcli "github.com/filecoin-project/lotus/cli"
 	"github.com/filecoin-project/lotus/lib/lotuslog"
 	"github.com/filecoin-project/lotus/lib/peermgr"
@@ -32,6 +33,7 @@ import (
 var clientAPIFlags struct {
 	apiAddr  string
 	apiToken string
+	repo     string
 }
 
 var clientAPIFlag = &cli.StringFlag{
@@ -44,23 +46,33 @@ var clientAPIFlag = &cli.StringFlag{
 
 var clientTokenFlag = &cli.StringFlag{
 	Name:        "api-token",
-	Usage:       "Authentication token for lily api.",
+	Usage:       "Authentication token for lily api. When not set the admin token held in the repository given by --repo is used.",
 	EnvVars:     []string{"LILY_API_TOKEN"},
 	Value:       "",
 	Destination: &clientAPIFlags.apiToken,
 }
 
+var clientRepoFlag = &cli.StringFlag{
+	Name:        "repo",
+	Usage:       "Path of the daemon's repository, whose admin token is used when --api-token is not set.",
+	EnvVars:     []string{"LILY_REPO"},
+	Value:       "~/.lotus",
+	Destination: &clientAPIFlags.repo,
+}
+
 // clientAPIFlagSet are used by commands that act as clients of a daemon's API
 var clientAPIFlagSet = []cli.Flag{
 	clientAPIFlag,
 	clientTokenFlag,
+	clientRepoFlag,
 }
 
 type daemonOpts struct {
-	repo      string
-	bootstrap bool // TODO: is this necessary - do we want to run lily in this mode?
-	config    string
-	genesis   string
+	repo       string
+	bootstrap  bool // TODO: is this necessary - do we want to run lily in this mode?
+	config     string
+	genesis    string
+	gasTracing bool
 }
 
 var daemonFlags daemonOpts
@@ -113,8 +125,15 @@ daemon falls out of sync. Start a watch using 'visor watch'.
 A walk job will start immediately. Start a walk using 'visor walk'. A walk may
 only be performed between heights that have been synchronized with the network.
 
-Note that jobs are not persisted between restarts of the daemon. See
-'visor help job' for more information on managing jobs being run by the daemon.
+Jobs may also be declared in the [Jobs] section of the config file. They are
+submitted once the daemon has synced with the chain and are validated when the
+daemon starts.
+
+Other jobs are persisted in the repository and resubmitted when the daemon
+restarts. Walks resume from the lowest height they reached. Completed jobs are
+removed from the repository and any other job may be removed using
+'lily job forget'. See 'visor help job' for more information on managing jobs
+being run by the daemon.
 `,
 
 	Flags: []cli.Flag{
@@ -146,6 +165,12 @@ Note that jobs are not persisted between restarts of the daemon. See
 			EnvVars:     []string{"LILY_GENESIS"},
 			Destination: &daemonFlags.genesis,
 		},
+		&cli.BoolFlag{
+			Name:        "gas-tracing",
+			Usage:       "Record gas charges in message execution traces. Required by the gastrace task.",
+			EnvVars:     []string{"LILY_GAS_TRACING"},
+			Destination: &daemonFlags.gasTracing,
+		},
 	},
 	Action: func(c *cli.Context) error {
 		lotuslog.SetupLogLevels()
@@ -164,6 +189,9 @@ Note that jobs are not persisted between restarts of the daemon. See
 		}
 		defer tcloser()
 
+		// the vm only reads this setting so it must be set before the node starts executing messages
+		vm.EnableGasTracing = daemonFlags.gasTracing
+
 		ctx := context.Background()
 		repoDir, err := homedir.Expand(daemonFlags.repo)
 		if err != nil {
@@ -261,6 +289,20 @@ Note that jobs are not persisted between restarts of the daemon. See
 			return xerrors.Errorf("initializing node: %w", err)
 		}
 
+		// resubmit jobs persisted by a previous run of the daemon
+		if nodeAPI, ok := api.(*lily.LilyNodeAPI); ok {
+			if err := nodeAPI.RestoreJobs(ctx); err != nil {
+				log.Errorw("failed to restore persisted jobs", "error", err)
+			}
+
+			// submit jobs declared in the config once the daemon has synced
+			go func() {
+				if err := nodeAPI.SubmitConfiguredJobs(ctx); err != nil {
+					log.Errorw("failed to submit configured jobs", "error", err)
+				}
+			}()
+		}
+
 		endpoint, err := r.APIEndpoint()
 		if err != nil {
 			return xerrors.Errorf("getting api endpoint: %w", err)
@@ -18,8 +18,9 @@ type gapOps struct {
 	storage  string
 	tasks    string
 	name     string
-	from     uint64
-	to       uint64
+	from     string
+	to       string
+	schedule string
 }
 
 var gapFlags gapOps
@@ -46,11 +47,12 @@ var GapFillCmd = &cli.Command{
 		},
 		&cli.StringFlag{
 			Name:        "api-token",
-			Usage:       "Authentication token for lily api.",
+			Usage:       "Authentication token for lily api. When not set the admin token held in the repository given by --repo is used.",
 			EnvVars:     []string{"LILY_API_TOKEN"},
 			Value:       "",
 			Destination: &gapFlags.apiToken,
 		},
+		clientRepoFlag,
 		&cli.StringFlag{
 			Name:        "storage",
 			Usage:       "Name of storage that results will be written to.",
@@ -69,18 +71,24 @@ var GapFillCmd = &cli.Command{
 			Value:       "",
 			Destination: &gapFlags.name,
 		},
-		&cli.Uint64Flag{
+		&cli.StringFlag{
 			Name:        "to",
-			Usage:       "to epoch to search for gaps in",
+			Usage:       "to epoch to search for gaps in, either a height or relative to the chain head such as 'head-10'",
 			Destination: &gapFlags.to,
 			Required:    true,
 		},
-		&cli.Uint64Flag{
+		&cli.StringFlag{
 			Name:        "from",
-			Usage:       "from epoch to search for gaps in",
+			Usage:       "from epoch to search for gaps in, either a height or relative to the chain head such as 'head-2880'",
 			Destination: &gapFlags.from,
 			Required:    true,
 		},
+		&cli.StringFlag{
+			Name:        "schedule",
+			Usage:       "Cron expression on which to run the job, such as '0 2 * * *' for every day at 02:00. If empty the job is run once immediately.",
+			Value:       "",
+			Destination: &gapFlags.schedule,
+		},
 	},
 	Action: func(cctx *cli.Context) error {
 		ctx := lotuscli.ReqContext(cctx)
@@ -110,8 +118,9 @@ var GapFillCmd = &cli.Command{
 			Storage:             gapFlags.storage,
 			Name:                fillName,
 			Tasks:               tasks,
-			To:                  gapFlags.to,
-			From:                gapFlags.from,
+			ToExpr:              gapFlags.to,
+			FromExpr:            gapFlags.from,
+			Schedule:            gapFlags.schedule,
 		})
 		if err != nil {
 			return err
@@ -136,11 +145,12 @@ var GapFindCmd = &cli.Command{
 		},
 		&cli.StringFlag{
 			Name:        "api-token",
-			Usage:       "Authentication token for lily api.",
+			Usage:       "Authentication token for lily api. When not set the admin token held in the repository given by --repo is used.",
 			EnvVars:     []string{"LILY_API_TOKEN"},
 			Value:       "",
 			Destination: &gapFlags.apiToken,
 		},
+		clientRepoFlag,
 		&cli.StringFlag{
 			Name:        "storage",
 			Usage:       "Name of storage that results will be written to.",
@@ -159,18 +169,24 @@ var GapFindCmd = &cli.Command{
 			Value:       "",
 			Destination: &gapFlags.tasks,
 		},
-		&cli.Uint64Flag{
+		&cli.StringFlag{
 			Name:        "to",
-			Usage:       "to epoch to search for gaps in",
+			Usage:       "to epoch to search for gaps in, either a height or relative to the chain head such as 'head-10'",
 			Destination: &gapFlags.to,
 			Required:    true,
 		},
-		&cli.Uint64Flag{
+		&cli.StringFlag{
 			Name:        "from",
-			Usage:       "from epoch to search for gaps in",
+			Usage:       "from epoch to search for gaps in, either a height or relative to the chain head such as 'head-2880'",
 			Destination: &gapFlags.from,
 			Required:    true,
 		},
+		&cli.StringFlag{
+			Name:        "schedule",
+			Usage:       "Cron expression on which to run the job, such as '0 2 * * *' for every day at 02:00. If empty the job is run once immediately.",
+			Value:       "",
+			Destination: &gapFlags.schedule,
+		},
 	},
 	Action: func(cctx *cli.Context) error {
 		ctx := lotuscli.ReqContext(cctx)
@@ -200,8 +216,9 @@ var GapFindCmd = &cli.Command{
 			Storage:             gapFlags.storage,
 			Tasks:               tasks,
 			Name:                findName,
-			To:                  gapFlags.to,
-			From:                gapFlags.from,
+			ToExpr:              gapFlags.to,
+			FromExpr:            gapFlags.from,
+			Schedule:            gapFlags.schedule,
 		})
 		if err != nil {
 			return err
@@ -150,15 +150,30 @@ General tasks that capture data present in on-chain tipsets:
   chaineconomics  Reads circulating supply information. Populates
                   the chain_economics model.
 
+  epochsummary    Summarizes each epoch in a single row, including the number
+                  of blocks and messages, gas used and limit, base fee, total
+                  burnt and miner tips and the block timestamps. Epochs without
+                  a tipset are recorded as null rounds. Populates the
+                  epoch_summaries model.
+
   messages        Captures data about messages that were carried in a tipset's
                   blocks. It is possible for the same message to appear in
                   multiple blocks within a single tipset. The block_messages
                   model captures the relationship between a message and the
                   blocks it appears in. Message parameters are parsed and
-                  serialized as JSON in the parsed_messages model.
+                  serialized as JSON in the parsed_messages model. The name
+                  of the method invoked by each message is recorded even
+                  when its parameters cannot be parsed.
 
                   The receipt is also captured for any messages that
-                  were executed.
+                  were executed, along with the symbolic name of its exit
+                  code. Return values of successful messages are
+                  parsed and serialized as JSON in the parsed_receipts
+                  model.
+
+                  Calls proposed to or approved by multisig actors are
+                  decoded down to their final recipient and captured in
+                  the multisig_inner_messages model.
 
                   Detailed information about gas usage by each message is
                   captured in the derived_gas_outputs model. A summary of
@@ -169,6 +184,20 @@ General tasks that capture data present in on-chain tipsets:
                   tipsets since receipts are carried in the tipset following
                   the one containing the messages.
 
+  messagenonces   Summarizes the nonces of the messages executed for each
+                  sender in a tipset, including the nonce expected from the
+                  sender's state and whether the nonces were contiguous.
+                  Messages executed with the same sender and nonce as a
+                  different message seen in a reverted tipset within chain
+                  finality are recorded as replacements. Populates the
+                  message_sender_nonces and message_nonce_replacements
+                  models.
+
+  networkversion  Records the network version, actors version and state tree
+                  version in effect at each epoch where any of them changed,
+                  along with the upgrade schedule of the network. Populates
+                  the network_versions and network_upgrades models.
+
 Tasks for capturing actor state changes. These tasks operate by performing a diff
 of an actor's state between two sequential tipsets:
 
@@ -177,6 +206,11 @@ of an actor's state between two sequential tipsets:
                        state to JSON. Populates the actors and actor_states
                        models.
 
+  actorlifecycle       Records an event for each actor that is created or deleted
+                       and for each actor whose code is changed by a network
+                       upgrade, along with its robust address where known.
+                       Populates the actor_lifecycle_events model.
+
   actorstatesinit      Captures changes to the init actor to provide mappings
                        between canonical ID-addresses and temporary actor
                        addresses or public keys. Populates the id_addresses model.
@@ -192,14 +226,22 @@ of an actor's state between two sequential tipsets:
                        miner_pre_commit_infos, miner_sector_infos,
                        miner_sector_events and miner_sector_deals models.
 
+  minersectorlifecycles
+                       Maintains a summary of the lifecycle of each sector from
+                       the sector events of miner actors, including the epochs
+                       of precommit, prove-commit, faults, recoveries,
+                       extensions and termination or expiration. Populates the
+                       miner_sector_lifecycles model.
+
   actorstatesmultisig  Analyzes changes to multisig actors to capture data about
                        multisig transactions. Populates the multisig_transactions
                        model.
 
   actorstatespower     Analyzes changes to the storage power to capture information
-                       about total power at each epoch and updates to miner power
-                       claims. Populates the chain_powers and power_actor_claims
-                       models.
+                       about total power and miner counts at each epoch and updates
+                       to miner power claims, including whether each claim meets
+                       the consensus minimum. Populates the chain_powers,
+                       power_actor_claims and power_actor_claim_consensus models.
 
   actorstatesreward    Captures changes in the reward actor state to provide
                        information about miner rewards for each epoch. Populates
@@ -207,9 +249,30 @@ of an actor's state between two sequential tipsets:
 
 Other tasks:
 
-  msapprovals  Captures approvals of multisig actors by interpreting the outcome
-               of approval messages sent on chain. Populates the
-               multisig_approvals model.
+  msapprovals      Captures approvals of multisig actors by interpreting the
+                   outcome of approval messages sent on chain. Populates the
+                   multisig_approvals model.
+
+  consensusfaults  Captures consensus faults reported to miner actors, including
+                   the block headers involved and the penalty and reporter
+                   reward paid during execution of the report. Populates the
+                   consensus_faults model.
+
+  blockrewards     Attributes the reward paid by the reward actor to each block
+                   in a tipset, including win count, gas reward and penalty.
+                   Populates the block_rewards model.
+
+  calltree         Records the tree of calls made while executing each message,
+                   including the depth, parent and order of each call among
+                   its siblings along with its exit code and the size of its
+                   return value. Populates the message_calls model.
+
+  gastrace         Records the gas charged while executing each message, summed
+                   by the name of the charge. Not run by default. Use the
+                   --gastrace-level option to sum charges for the whole message
+                   (message) or for each call it makes (call). The daemon must
+                   be started with the --gas-tracing option, otherwise the task
+                   fails. Populates the message_gas_charges model.
 `,
 	},
 
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/lotus/chain/types"
+	lotuscli "github.com/filecoin-project/lotus/cli"
+	"github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain"
+	"github.com/filecoin-project/lily/lens/lily"
+	"github.com/filecoin-project/lily/tasks/gastrace"
+)
+
+type indexOps struct {
+	tasks    string
+	storage  string
+	gasTrace string
+}
+
+var indexFlags indexOps
+
+var IndexCmd = &cli.Command{
+	Name:      "index",
+	Usage:     "Extract the models of a single tipset and print them as JSON.",
+	ArgsUsage: "<height|tipset>",
+	Description: `Runs the given tasks over a single tipset and prints the extracted models, keyed
+by table, and the processing report of each task as JSON without creating a job.
+The tipset is given either as a height on the heaviest chain or as a comma
+separated list of block cids. The head of the chain and tipsets that are not on
+the heaviest chain cannot be indexed since tasks need the tipset that follows it.
+
+With --storage the models are also persisted to the named storage.`,
+	Flags: flagSet(
+		clientAPIFlagSet,
+		[]cli.Flag{
+			&cli.StringFlag{
+				Name:        "tasks",
+				Usage:       "Comma separated list of tasks to run.",
+				Value:       strings.Join([]string{chain.BlocksTask, chain.MessagesTask, chain.ChainEconomicsTask, chain.ActorStatesRawTask}, ","),
+				Destination: &indexFlags.tasks,
+			},
+			&cli.StringFlag{
+				Name:        "storage",
+				Usage:       "Name of storage that results will also be written to.",
+				Value:       "",
+				Destination: &indexFlags.storage,
+			},
+			&cli.StringFlag{
+				Name:        "gastrace-level",
+				Usage:       "Level of detail recorded by the gastrace task, one of message or call.",
+				Value:       string(gastrace.LevelMessage),
+				Destination: &indexFlags.gasTrace,
+			},
+		},
+	),
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return xerrors.Errorf("expected a height or tipset")
+		}
+
+		ctx := lotuscli.ReqContext(cctx)
+		api, closer, err := GetAPI(ctx, clientAPIFlags.apiAddr, clientAPIFlags.apiToken)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		var tsk types.TipSetKey
+		if height, err := strconv.ParseInt(cctx.Args().First(), 10, 64); err == nil {
+			ts, err := api.ChainGetTipSetByHeight(ctx, abi.ChainEpoch(height), types.EmptyTSK)
+			if err != nil {
+				return xerrors.Errorf("get tipset at height %d: %w", height, err)
+			}
+			tsk = ts.Key()
+		} else {
+			tsk, err = parseTipSetKey(cctx.Args().First())
+			if err != nil {
+				return err
+			}
+		}
+
+		res, err := api.LilyIndex(ctx, &lily.LilyIndexConfig{
+			TipSet:        tsk,
+			Tasks:         strings.Split(indexFlags.tasks, ","),
+			Storage:       indexFlags.storage,
+			GasTraceLevel: indexFlags.gasTrace,
+		})
+		if err != nil {
+			return err
+		}
+
+		prettyRes, err := json.MarshalIndent(res, "", "\t")
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(os.Stdout, "%s\n", prettyRes); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+// parseTipSetKey parses a tipset key written as a comma separated list of block cids, optionally enclosed in braces.
+func parseTipSetKey(s string) (types.TipSetKey, error) {
+	s = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), "{"), "}")
+	var cids []cid.Cid
+	for _, c := range strings.Split(s, ",") {
+		parsed, err := cid.Decode(strings.TrimSpace(c))
+		if err != nil {
+			return types.EmptyTSK, xerrors.Errorf("parse tipset block cid %q: %w", c, err)
+		}
+		cids = append(cids, parsed)
+	}
+	return types.NewTipSetKey(cids...), nil
+}
@@ -1,9 +1,12 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	lotuscli "github.com/filecoin-project/lotus/cli"
 	"github.com/urfave/cli/v2"
@@ -18,11 +21,19 @@ var JobCmd = &cli.Command{
 		JobStartCmd,
 		JobStopCmd,
 		JobListCmd,
+		JobForgetCmd,
+		JobRemoveCmd,
+		JobShowCmd,
+		JobLogsCmd,
+		JobWaitCmd,
 	},
 }
 
 var jobControlFlags struct {
-	ID int
+	ID       int
+	Lines    int
+	Progress bool
+	Interval time.Duration
 }
 
 var JobStartCmd = &cli.Command{
@@ -77,9 +88,47 @@ var JobStopCmd = &cli.Command{
 	},
 }
 
+var JobForgetCmd = &cli.Command{
+	Name:  "forget",
+	Usage: "forget a job so it is not resubmitted when the daemon restarts.",
+	Description: `Jobs submitted to the daemon are persisted in its repository and resubmitted
+when the daemon restarts, with walks resuming from the lowest height they
+reached. Forgetting a job removes it from the repository but does not stop it;
+use 'lily job stop' to stop a running job. Completed jobs are forgotten
+automatically.`,
+	Flags: flagSet(
+		clientAPIFlagSet,
+		[]cli.Flag{
+			&cli.IntFlag{
+				Name:        "id",
+				Usage:       "Identifier of job to forget",
+				Required:    true,
+				Destination: &jobControlFlags.ID,
+			},
+		},
+	),
+	Action: func(cctx *cli.Context) error {
+		ctx := lotuscli.ReqContext(cctx)
+		api, closer, err := GetAPI(ctx, clientAPIFlags.apiAddr, clientAPIFlags.apiToken)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		return api.LilyJobForget(ctx, schedule.JobID(jobControlFlags.ID))
+	},
+}
+
 var JobListCmd = &cli.Command{
 	Name:  "list",
 	Usage: "list all jobs and their status",
+	Description: `Lists the jobs known to the daemon. Walk, fill and watch jobs also report their
+progress: the height most recently processed, the number of heights processed
+and remaining, the rate in tipsets per minute, an estimated time to completion
+for walks and fills and the last error encountered while processing a height.
+For watches the remaining heights are the number of heights behind the head of
+the chain. Jobs run on a schedule report their cron expression and the time of
+their next run.`,
 	Flags: flagSet(
 		clientAPIFlagSet,
 	),
@@ -105,3 +154,204 @@ var JobListCmd = &cli.Command{
 		return nil
 	},
 }
+
+var JobRemoveCmd = &cli.Command{
+	Name:  "rm",
+	Usage: "remove a stopped job from the daemon.",
+	Description: `Removes a job from the list of jobs known to the daemon and from its repository
+so it is not resubmitted when the daemon restarts. Running jobs must be stopped
+with 'lily job stop' before they can be removed.`,
+	Flags: flagSet(
+		clientAPIFlagSet,
+		[]cli.Flag{
+			&cli.IntFlag{
+				Name:        "id",
+				Usage:       "Identifier of job to remove",
+				Required:    true,
+				Destination: &jobControlFlags.ID,
+			},
+		},
+	),
+	Action: func(cctx *cli.Context) error {
+		ctx := lotuscli.ReqContext(cctx)
+		api, closer, err := GetAPI(ctx, clientAPIFlags.apiAddr, clientAPIFlags.apiToken)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		return api.LilyJobRemove(ctx, schedule.JobID(jobControlFlags.ID))
+	},
+}
+
+var JobShowCmd = &cli.Command{
+	Name:  "show",
+	Usage: "show the detail of a job",
+	Description: `Shows the status of a job as reported by 'lily job list' together with the
+outcomes of the most recent heights it processed: when each height was
+processed, how long it took and any errors reported by its tasks or
+encountered while persisting their data.`,
+	Flags: flagSet(
+		clientAPIFlagSet,
+		[]cli.Flag{
+			&cli.IntFlag{
+				Name:        "id",
+				Usage:       "Identifier of job to show",
+				Required:    true,
+				Destination: &jobControlFlags.ID,
+			},
+		},
+	),
+	Action: func(cctx *cli.Context) error {
+		ctx := lotuscli.ReqContext(cctx)
+		api, closer, err := GetAPI(ctx, clientAPIFlags.apiAddr, clientAPIFlags.apiToken)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		job, err := api.LilyJobGet(ctx, schedule.JobID(jobControlFlags.ID))
+		if err != nil {
+			return err
+		}
+		prettyJob, err := json.MarshalIndent(job, "", "\t")
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(os.Stdout, "%s\n", prettyJob); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+var JobLogsCmd = &cli.Command{
+	Name:  "logs",
+	Usage: "show the most recent log lines of a job",
+	Description: `Shows the most recent lines logged by a job at info level and above. The
+daemon keeps the last 500 lines of each job while the job is known to it.`,
+	Flags: flagSet(
+		clientAPIFlagSet,
+		[]cli.Flag{
+			&cli.IntFlag{
+				Name:        "id",
+				Usage:       "Identifier of job to show logs for",
+				Required:    true,
+				Destination: &jobControlFlags.ID,
+			},
+			&cli.IntFlag{
+				Name:        "lines",
+				Aliases:     []string{"n"},
+				Usage:       "Number of lines to show, 0 for all retained lines",
+				Value:       50,
+				Destination: &jobControlFlags.Lines,
+			},
+		},
+	),
+	Action: func(cctx *cli.Context) error {
+		ctx := lotuscli.ReqContext(cctx)
+		api, closer, err := GetAPI(ctx, clientAPIFlags.apiAddr, clientAPIFlags.apiToken)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		lines, err := api.LilyJobLogs(ctx, schedule.JobID(jobControlFlags.ID), jobControlFlags.Lines)
+		if err != nil {
+			return err
+		}
+		for _, l := range lines {
+			if _, err := fmt.Fprintf(os.Stdout, "%s\t%s\t%s\t%s\n", l.Time.Format(time.RFC3339), strings.ToUpper(l.Level), l.Message, l.Fields); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var JobWaitCmd = &cli.Command{
+	Name:  "wait",
+	Usage: "wait for a job to complete",
+	Description: `Blocks until the current execution of a job ends and prints the final state of
+the job. The command exits with status 0 if the job ran to completion, 1 if it
+failed and 2 if it was stopped before completing. With --progress the progress
+of the job is printed to stderr while waiting.
+
+Jobs that run on a schedule or restart on completion only end when they are
+stopped or fail.`,
+	Flags: flagSet(
+		clientAPIFlagSet,
+		[]cli.Flag{
+			&cli.IntFlag{
+				Name:        "id",
+				Usage:       "Identifier of job to wait for",
+				Required:    true,
+				Destination: &jobControlFlags.ID,
+			},
+			&cli.BoolFlag{
+				Name:        "progress",
+				Usage:       "Print the progress of the job to stderr while waiting",
+				Destination: &jobControlFlags.Progress,
+			},
+			&cli.DurationFlag{
+				Name:        "interval",
+				Usage:       "How often to print the progress of the job",
+				Value:       30 * time.Second,
+				Destination: &jobControlFlags.Interval,
+			},
+		},
+	),
+	Action: func(cctx *cli.Context) error {
+		ctx := lotuscli.ReqContext(cctx)
+		api, closer, err := GetAPI(ctx, clientAPIFlags.apiAddr, clientAPIFlags.apiToken)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		id := schedule.JobID(jobControlFlags.ID)
+
+		if jobControlFlags.Progress {
+			pctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			go func() {
+				ticker := time.NewTicker(jobControlFlags.Interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-pctx.Done():
+						return
+					case <-ticker.C:
+						job, err := api.LilyJobGet(pctx, id)
+						if err != nil || job.Progress == nil {
+							continue
+						}
+						p := job.Progress
+						_, _ = fmt.Fprintf(os.Stderr, "height: %d processed: %d remaining: %d rate: %.1f tipsets/min eta: %s\n", p.CurrentHeight, p.Processed, p.Remaining, p.TipSetsPerMinute, p.ETA.Round(time.Second))
+					}
+				}
+			}()
+		}
+
+		res, err := api.LilyJobWait(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		prettyJob, err := json.MarshalIndent(res, "", "\t")
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(os.Stdout, "%s\n", prettyJob); err != nil {
+			return err
+		}
+
+		if res.Completed {
+			return nil
+		}
+		if res.Error != "" {
+			return cli.Exit(fmt.Sprintf("job %d failed: %s", id, res.Error), 1)
+		}
+		return cli.Exit(fmt.Sprintf("job %d stopped before completing", id), 2)
+	},
+}
@@ -2,20 +2,28 @@ package commands
 
 import (
 	"context"
+	"io/ioutil"
 	"net/http"
+	"path/filepath"
 	"strings"
 
 	"github.com/filecoin-project/go-jsonrpc"
 	cliutil "github.com/filecoin-project/lotus/cli/util"
 	"github.com/filecoin-project/lotus/node"
 	"github.com/filecoin-project/lotus/node/repo"
+	"github.com/mitchellh/go-homedir"
 	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/lily/lens/lily"
 )
 
+// GetAPI connects to the api of a daemon. When no token is given the admin token written to the repository given by
+// --repo is used, if it can be read, so that commands run alongside the daemon are not limited to read permissions.
 func GetAPI(ctx context.Context, addrStr string, token string) (lily.LilyAPI, jsonrpc.ClientCloser, error) {
 	addrStr = strings.TrimSpace(addrStr)
+	if token == "" {
+		token = repoToken()
+	}
 
 	ainfo := cliutil.APIInfo{Addr: addrStr, Token: []byte(token)}
 
@@ -27,6 +35,24 @@ func GetAPI(ctx context.Context, addrStr string, token string) (lily.LilyAPI, js
 	return NewSentinelNodeRPC(ctx, addr, ainfo.AuthHeader())
 }
 
+// repoToken returns the api token held in the repository given by the --repo flag, and an empty string if it cannot
+// be read. The repository's token grants admin permissions.
+func repoToken() string {
+	repoPath := clientAPIFlags.repo
+	if repoPath == "" {
+		repoPath = clientRepoFlag.Value
+	}
+	repoPath, err := homedir.Expand(repoPath)
+	if err != nil {
+		return ""
+	}
+	token, err := ioutil.ReadFile(filepath.Join(repoPath, "token"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(token))
+}
+
 func NewSentinelNodeRPC(ctx context.Context, addr string, requestHeader http.Header) (lily.LilyAPI, jsonrpc.ClientCloser, error) {
 	var res lily.LilyAPIStruct
 	closer, err := jsonrpc.NewMergeClient(ctx, addr, "Filecoin",
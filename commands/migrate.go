@@ -79,6 +79,11 @@ var MigrateCmd = &cli.Command{
 				Value: false,
 				Usage: "Migrate the schema to the latest version.",
 			},
+			&cli.BoolFlag{
+				Name:  "timestamp-indexes",
+				Value: false,
+				Usage: "Create an index on the time column of every table with one, after any migration. Indexes are built concurrently so writes are not blocked.",
+			},
 		},
 	),
 	Action: func(cctx *cli.Context) error {
@@ -99,11 +104,21 @@ var MigrateCmd = &cli.Command{
 				return xerrors.Errorf("invalid schema version: %w", err)
 			}
 
-			return db.MigrateSchemaTo(ctx, targetVersion)
+			if err := db.MigrateSchemaTo(ctx, targetVersion); err != nil {
+				return err
+			}
+			return createTimestampIndexes(cctx, db)
 		}
 
 		if cctx.Bool("latest") {
-			return db.MigrateSchema(ctx)
+			if err := db.MigrateSchema(ctx); err != nil {
+				return err
+			}
+			return createTimestampIndexes(cctx, db)
+		}
+
+		if cctx.Bool("timestamp-indexes") {
+			return createTimestampIndexes(cctx, db)
 		}
 
 		dbVersion, latestVersion, err := db.GetSchemaVersions(ctx)
@@ -121,3 +136,11 @@ var MigrateCmd = &cli.Command{
 		return nil
 	},
 }
+
+// createTimestampIndexes creates the indexes on time columns when requested by the timestamp-indexes flag.
+func createTimestampIndexes(cctx *cli.Context, db *storage.Database) error {
+	if !cctx.Bool("timestamp-indexes") {
+		return nil
+	}
+	return db.CreateTimestampIndexes(cctx.Context)
+}
@@ -34,14 +34,17 @@ var (
 const (
 	// When changing these, update docs/API.md too
 
-	PermRead  auth.Permission = "read" // default
-	PermWrite auth.Permission = "write"
+	PermRead  auth.Permission = "read"  // default, query the chain and the state of jobs
+	PermJob   auth.Permission = "job"   // start, stop and remove existing jobs
+	PermWrite auth.Permission = "write" // create jobs and change the daemon's logging
 	PermSign  auth.Permission = "sign"  // Use wallet keys for signing
-	PermAdmin auth.Permission = "admin" // Manage permissions
+	PermAdmin auth.Permission = "admin" // Manage permissions and shut down the daemon
 )
 
 var (
-	AllPermissions = []auth.Permission{PermRead, PermWrite, PermSign, PermAdmin}
+	// AllPermissions are ordered from least to most privileged. A caller holding a permission may call any method
+	// requiring that permission or one before it.
+	AllPermissions = []auth.Permission{PermRead, PermJob, PermWrite, PermSign, PermAdmin}
 	DefaultPerms   = []auth.Permission{PermRead}
 )
 
@@ -77,11 +80,62 @@ func proxy(in interface{}, out interface{}) {
 
 func PermissionedSentinelAPI(a lily.LilyAPI) lily.LilyAPI {
 	var out lily.LilyAPIStruct
-	auth.PermissionedProxy(AllPermissions, DefaultPerms, a, &out.Internal)
-	auth.PermissionedProxy(AllPermissions, DefaultPerms, a, &out.CommonStruct.Internal)
+	permissionedProxy(a, &out.Internal)
+	permissionedProxy(a, &out.CommonStruct.Internal)
 	return &out
 }
 
+// permissionedProxy is like auth.PermissionedProxy but treats permissions as levels: a method may be called by a
+// caller holding the permission named in its perm tag or any permission after it in AllPermissions.
+func permissionedProxy(in interface{}, out interface{}) {
+	rint := reflect.ValueOf(out).Elem()
+	ra := reflect.ValueOf(in)
+
+	for f := 0; f < rint.NumField(); f++ {
+		field := rint.Type().Field(f)
+		required := auth.Permission(field.Tag.Get("perm"))
+		if permLevel(required) < 0 {
+			panic(xerrors.Errorf("method %s has unknown permission %q", field.Name, required))
+		}
+
+		fn := ra.MethodByName(field.Name)
+
+		rint.Field(f).Set(reflect.MakeFunc(field.Type, func(args []reflect.Value) (results []reflect.Value) {
+			ctx := args[0].Interface().(context.Context)
+			if hasPermLevel(ctx, required) {
+				return fn.Call(args)
+			}
+
+			err := xerrors.Errorf("missing permission to invoke '%s' (need '%s')", field.Name, required)
+			rerr := reflect.ValueOf(&err).Elem()
+			if field.Type.NumOut() == 2 {
+				return []reflect.Value{reflect.Zero(field.Type.Out(0)), rerr}
+			}
+			return []reflect.Value{rerr}
+		}))
+	}
+}
+
+// permLevel returns the position of perm in AllPermissions, or -1 if it is not a known permission.
+func permLevel(perm auth.Permission) int {
+	for i, p := range AllPermissions {
+		if p == perm {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasPermLevel reports whether the caller holds the required permission or a more privileged one.
+func hasPermLevel(ctx context.Context, required auth.Permission) bool {
+	for _, p := range AllPermissions[permLevel(required):] {
+		if auth.HasPerm(ctx, DefaultPerms, p) {
+			return true
+		}
+	}
+	return false
+}
+
 func ServeRPC(a lily.LilyAPI, stop node.StopFunc, addr multiaddr.Multiaddr, shutdownCh <-chan struct{}, maxRequestSize int64) error {
 	serverOptions := make([]jsonrpc.ServerOption, 0)
 	if maxRequestSize != 0 { // config set
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-jsonrpc/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/lens/lily"
+	"github.com/filecoin-project/lily/schedule"
+)
+
+func TestHasPermLevel(t *testing.T) {
+	ctx := context.Background()
+
+	// callers without a token hold the default permissions
+	assert.True(t, hasPermLevel(ctx, PermRead))
+	assert.False(t, hasPermLevel(ctx, PermJob))
+
+	jobCtx := auth.WithPerm(ctx, []auth.Permission{PermJob})
+	assert.True(t, hasPermLevel(jobCtx, PermRead))
+	assert.True(t, hasPermLevel(jobCtx, PermJob))
+	assert.False(t, hasPermLevel(jobCtx, PermWrite))
+
+	// tokens created by lotus hold all of its permissions but not the job permission
+	lotusCtx := auth.WithPerm(ctx, []auth.Permission{PermRead, PermWrite, PermSign, PermAdmin})
+	for _, p := range AllPermissions {
+		assert.True(t, hasPermLevel(lotusCtx, p), p)
+	}
+
+	readCtx := auth.WithPerm(ctx, []auth.Permission{PermRead})
+	assert.False(t, hasPermLevel(readCtx, PermWrite))
+	assert.False(t, hasPermLevel(readCtx, PermAdmin))
+}
+
+func TestPermissionedSentinelAPI(t *testing.T) {
+	var called []string
+	var inner lily.LilyAPIStruct
+	inner.Internal.LilyWalk = func(context.Context, *lily.LilyWalkConfig) (schedule.JobID, error) {
+		called = append(called, "LilyWalk")
+		return 1, nil
+	}
+	inner.Internal.Shutdown = func(context.Context) error {
+		called = append(called, "Shutdown")
+		return nil
+	}
+	inner.Internal.LilyJobList = func(context.Context) ([]schedule.JobResult, error) {
+		called = append(called, "LilyJobList")
+		return nil, nil
+	}
+
+	a := PermissionedSentinelAPI(&inner)
+
+	// a read token may only call read methods
+	readCtx := auth.WithPerm(context.Background(), []auth.Permission{PermRead})
+	_, err := a.LilyWalk(readCtx, &lily.LilyWalkConfig{})
+	assert.Error(t, err)
+	assert.Error(t, a.Shutdown(readCtx))
+	_, err = a.LilyJobList(readCtx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"LilyJobList"}, called)
+
+	// an admin token may call everything
+	called = nil
+	adminCtx := auth.WithPerm(context.Background(), []auth.Permission{PermAdmin})
+	id, err := a.LilyWalk(adminCtx, &lily.LilyWalkConfig{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, id)
+	require.NoError(t, a.Shutdown(adminCtx))
+	assert.Equal(t, []string{"LilyWalk", "Shutdown"}, called)
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	lotuscli "github.com/filecoin-project/lotus/cli"
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/lily/lens/lily"
+)
+
+type viewOps struct {
+	apiAddr     string
+	apiToken    string
+	storage     string
+	name        string
+	refreshRate time.Duration
+	schedule    string
+}
+
+var viewFlags viewOps
+
+var ViewCmd = &cli.Command{
+	Name:  "view",
+	Usage: "Launch jobs managing the materialized views of a database",
+	Subcommands: []*cli.Command{
+		ViewRefreshCmd,
+	},
+}
+
+var ViewRefreshCmd = &cli.Command{
+	Name:  "refresh",
+	Usage: "Refresh the materialized views of a database",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "api",
+			Usage:       "Address of lily api in multiaddr format.",
+			EnvVars:     []string{"LILY_API"},
+			Value:       "/ip4/127.0.0.1/tcp/1234",
+			Destination: &viewFlags.apiAddr,
+		},
+		&cli.StringFlag{
+			Name:        "api-token",
+			Usage:       "Authentication token for lily api. When not set the admin token held in the repository given by --repo is used.",
+			EnvVars:     []string{"LILY_API_TOKEN"},
+			Value:       "",
+			Destination: &viewFlags.apiToken,
+		},
+		clientRepoFlag,
+		&cli.StringFlag{
+			Name:        "storage",
+			Usage:       "Name of the database storage whose views are refreshed.",
+			Value:       "",
+			Destination: &viewFlags.storage,
+			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "name",
+			Usage:       "Name of job for easy identification later.",
+			Value:       "",
+			Destination: &viewFlags.name,
+		},
+		&cli.DurationFlag{
+			Name:        "refresh-rate",
+			Usage:       "How often to refresh the views while the job runs. If zero the views are refreshed once each time the job runs.",
+			Value:       0,
+			Destination: &viewFlags.refreshRate,
+		},
+		&cli.StringFlag{
+			Name:        "schedule",
+			Usage:       "Cron expression on which to run the job, such as '0 2 * * *' for every day at 02:00. If empty the job is run once immediately.",
+			Value:       "",
+			Destination: &viewFlags.schedule,
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		ctx := lotuscli.ReqContext(cctx)
+
+		api, closer, err := GetAPI(ctx, viewFlags.apiAddr, viewFlags.apiToken)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		refreshName := fmt.Sprintf("viewrefresh_%d", time.Now().Unix())
+		if viewFlags.name != "" {
+			refreshName = viewFlags.name
+		}
+
+		refreshID, err := api.LilyViewRefresh(ctx, &lily.LilyViewRefreshConfig{
+			RestartOnFailure:    false,
+			RestartOnCompletion: false,
+			RestartDelay:        0,
+			Storage:             viewFlags.storage,
+			Name:                refreshName,
+			RefreshRate:         viewFlags.refreshRate,
+			Schedule:            viewFlags.schedule,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(os.Stdout, "Created View Refresh Job: %d\n", refreshID); err != nil {
+			return err
+		}
+		return nil
+	},
+}
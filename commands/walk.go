@@ -12,6 +12,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/filecoin-project/lily/chain"
+	"github.com/filecoin-project/lily/tasks/gastrace"
 )
 
 type walkOps struct {
@@ -23,6 +24,7 @@ type walkOps struct {
 	apiAddr  string
 	apiToken string
 	name     string
+	gasTrace string
 }
 
 var walkFlags walkOps
@@ -70,17 +72,24 @@ var WalkCmd = &cli.Command{
 		},
 		&cli.StringFlag{
 			Name:        "api-token",
-			Usage:       "Authentication token for lily api.",
+			Usage:       "Authentication token for lily api. When not set the admin token held in the repository given by --repo is used.",
 			EnvVars:     []string{"LILY_API_TOKEN"},
 			Value:       "",
 			Destination: &walkFlags.apiToken,
 		},
+		clientRepoFlag,
 		&cli.StringFlag{
 			Name:        "name",
 			Usage:       "Name of job for easy identification later.",
 			Value:       "",
 			Destination: &walkFlags.name,
 		},
+		&cli.StringFlag{
+			Name:        "gastrace-level",
+			Usage:       "Level of detail recorded by the gastrace task, one of message or call.",
+			Value:       string(gastrace.LevelMessage),
+			Destination: &walkFlags.gasTrace,
+		},
 	},
 	Action: func(cctx *cli.Context) error {
 		ctx := lotuscli.ReqContext(cctx)
@@ -100,6 +109,7 @@ var WalkCmd = &cli.Command{
 			RestartOnCompletion: false,
 			RestartOnFailure:    false,
 			Storage:             walkFlags.storage,
+			GasTraceLevel:       walkFlags.gasTrace,
 		}
 
 		api, closer, err := GetAPI(ctx, walkFlags.apiAddr, walkFlags.apiToken)
@@ -22,6 +22,7 @@ type watchOps struct {
 	apiAddr    string
 	apiToken   string
 	name       string
+	gasTrace   string
 }
 
 var watchFlags watchOps
@@ -63,17 +64,24 @@ var WatchCmd = &cli.Command{
 		},
 		&cli.StringFlag{
 			Name:        "api-token",
-			Usage:       "Authentication token for lily api.",
+			Usage:       "Authentication token for lily api. When not set the admin token held in the repository given by --repo is used.",
 			EnvVars:     []string{"LILY_API_TOKEN"},
 			Value:       "",
 			Destination: &watchFlags.apiToken,
 		},
+		clientRepoFlag,
 		&cli.StringFlag{
 			Name:        "name",
 			Usage:       "Name of job for easy identification later.",
 			Value:       "",
 			Destination: &watchFlags.name,
 		},
+		&cli.StringFlag{
+			Name:        "gastrace-level",
+			Usage:       "Level of detail recorded by the gastrace task, one of message or call.",
+			Value:       string(gastrace.LevelMessage),
+			Destination: &watchFlags.gasTrace,
+		},
 	},
 	Action: func(cctx *cli.Context) error {
 		ctx := lotuscli.ReqContext(cctx)
@@ -92,6 +100,7 @@ var WatchCmd = &cli.Command{
 			RestartOnCompletion: false,
 			RestartOnFailure:    true,
 			Storage:             watchFlags.storage,
+			GasTraceLevel:       watchFlags.gasTrace,
 		}
 
 		api, closer, err := GetAPI(ctx, watchFlags.apiAddr, watchFlags.apiToken)
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateJobs(t *testing.T) {
+	known := []string{"blocks", "messages"}
+
+	cfg, err := FromReader(strings.NewReader(`
+[Storage.Postgresql.Database1]
+  URL = "postgres://localhost:5432/postgres"
+
+[Storage.File.CSV]
+  Format = "CSV"
+  Path = "/tmp"
+
+[Jobs.Watch.Watch1]
+  Tasks = ["blocks", "messages"]
+  Storage = "CSV"
+  Window = "30s"
+
+[Jobs.GapFill.Fill1]
+  Tasks = ["blocks"]
+  Storage = "Database1"
+  To = 100
+`), DefaultConf())
+	require.NoError(t, err)
+	require.NoError(t, cfg.ValidateJobs(known))
+	assert.Equal(t, []string{"blocks", "messages"}, cfg.Jobs.Watch["Watch1"].Tasks)
+
+	cfg.Jobs.Watch["Watch1"] = WatchJobConf{Tasks: []string{"unknown"}}
+	assert.Error(t, cfg.ValidateJobs(known))
+
+	cfg.Jobs.Watch["Watch1"] = WatchJobConf{Storage: "Database2"}
+	assert.Error(t, cfg.ValidateJobs(known))
+
+	// only watches can publish to subscribers
+	cfg.Jobs.Watch["Watch1"] = WatchJobConf{Storage: "Stream"}
+	require.NoError(t, cfg.ValidateJobs(known))
+	cfg.Jobs.Walk = map[string]WalkJobConf{"Walk1": {Storage: "Stream", To: 100}}
+	assert.Error(t, cfg.ValidateJobs(known))
+	delete(cfg.Jobs.Walk, "Walk1")
+
+	// gaps can only be filled using a database
+	delete(cfg.Jobs.Watch, "Watch1")
+	cfg.Jobs.GapFill["Fill1"] = GapJobConf{Storage: "CSV"}
+	assert.Error(t, cfg.ValidateJobs(known))
+}
@@ -47,6 +47,7 @@ type ChainAPI interface {
 
 type StateAPI interface {
 	StateGetActor(ctx context.Context, addr address.Address, tsk types.TipSetKey) (*types.Actor, error)
+	StateLookupID(ctx context.Context, addr address.Address, tsk types.TipSetKey) (address.Address, error)
 	StateListActors(context.Context, types.TipSetKey) ([]address.Address, error)
 	StateChangedActors(context.Context, cid.Cid, cid.Cid) (map[string]types.Actor, error)
 
@@ -111,4 +112,5 @@ const (
 type ActorStateChange struct {
 	Actor      types.Actor
 	ChangeType ChangeType
+	PrevCode   cid.Cid // code of the actor before a modification, undefined for other types of change
 }
@@ -12,22 +12,47 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 
 	"github.com/filecoin-project/lily/schedule"
+	"github.com/filecoin-project/lily/storage"
 )
 
 type LilyAPI interface {
-	// NOTE: when adding daemon methods here, don't forget to add to the implementation to LilyAPIStruct too
-
-	AuthVerify(ctx context.Context, token string) ([]auth.Permission, error)
-
-	LilyWatch(ctx context.Context, cfg *LilyWatchConfig) (schedule.JobID, error)
-	LilyWalk(ctx context.Context, cfg *LilyWalkConfig) (schedule.JobID, error)
-
-	LilyJobStart(ctx context.Context, ID schedule.JobID) error
-	LilyJobStop(ctx context.Context, ID schedule.JobID) error
-	LilyJobList(ctx context.Context) ([]schedule.JobResult, error)
-
-	LilyGapFind(ctx context.Context, cfg *LilyGapFindConfig) (schedule.JobID, error)
-	LilyGapFill(ctx context.Context, cfg *LilyGapFillConfig) (schedule.JobID, error)
+	// NOTE: when adding daemon methods here, don't forget to add to the implementation to LilyAPIStruct too, with a
+	// perm tag matching the method's perm annotation. Permissions are ordered read < job < write < admin and a caller
+	// holding a permission may call any method requiring it or a lesser permission.
+
+	AuthVerify(ctx context.Context, token string) ([]auth.Permission, error) //perm:read
+	// AuthNew creates a token granting the given permissions.
+	AuthNew(ctx context.Context, perms []auth.Permission) ([]byte, error) //perm:admin
+
+	LilyWatch(ctx context.Context, cfg *LilyWatchConfig) (schedule.JobID, error) //perm:write
+	LilyWalk(ctx context.Context, cfg *LilyWalkConfig) (schedule.JobID, error)   //perm:write
+
+	LilyJobStart(ctx context.Context, ID schedule.JobID) error     //perm:job
+	LilyJobStop(ctx context.Context, ID schedule.JobID) error      //perm:job
+	LilyJobList(ctx context.Context) ([]schedule.JobResult, error) //perm:read
+	// LilyJobForget removes a job from the jobs that are resubmitted when the daemon restarts.
+	LilyJobForget(ctx context.Context, ID schedule.JobID) error //perm:job
+	// LilyJobRemove removes a stopped job from the daemon, including from the jobs that are resubmitted when it restarts.
+	LilyJobRemove(ctx context.Context, ID schedule.JobID) error //perm:job
+	// LilyJobGet returns the detail of a job, including its most recent processing outcomes.
+	LilyJobGet(ctx context.Context, ID schedule.JobID) (*schedule.JobDetail, error) //perm:read
+	// LilyJobLogs returns up to n of the most recent lines logged by a job, all retained lines if n is zero.
+	LilyJobLogs(ctx context.Context, ID schedule.JobID, n int) ([]schedule.LogLine, error) //perm:read
+	// LilyJobWait blocks until the current execution of a job ends and returns the final state of the job.
+	LilyJobWait(ctx context.Context, ID schedule.JobID) (*schedule.JobResult, error) //perm:read
+
+	LilyGapFind(ctx context.Context, cfg *LilyGapFindConfig) (schedule.JobID, error) //perm:write
+	LilyGapFill(ctx context.Context, cfg *LilyGapFillConfig) (schedule.JobID, error) //perm:write
+
+	// LilyViewRefresh starts a job refreshing the materialized views of a database, optionally on a schedule.
+	LilyViewRefresh(ctx context.Context, cfg *LilyViewRefreshConfig) (schedule.JobID, error) //perm:write
+
+	// LilySubscribe streams the batches of models persisted by a watch using a publishing storage. The channel is
+	// closed when the subscriber falls too far behind.
+	LilySubscribe(ctx context.Context, cfg *LilySubscribeConfig) (<-chan *storage.Batch, error) //perm:read
+
+	// LilyIndex extracts the models of a single tipset without creating a job, optionally also persisting them.
+	LilyIndex(ctx context.Context, cfg *LilyIndexConfig) (*LilyIndexResult, error) //perm:write
 
 	// SyncState returns the current status of the chain sync system.
 	SyncState(context.Context) (*api.SyncState, error) //perm:read
@@ -44,20 +69,20 @@ type LilyAPI interface {
 	ChainGetParentMessages(context.Context, cid.Cid) ([]api.Message, error)                            //perm:read
 
 	// trigger graceful shutdown
-	Shutdown(context.Context) error
+	Shutdown(context.Context) error //perm:admin
 
 	// LogList returns a list of loggers
 	LogList(context.Context) ([]string, error)         //perm:write
 	LogSetLevel(context.Context, string, string) error //perm:write
 
 	// ID returns peerID of libp2p node backing this API
-	ID(context.Context) (peer.ID, error) //perm:read
-	NetAutoNatStatus(ctx context.Context) (i api.NatInfo, err error)
-	NetPeers(context.Context) ([]peer.AddrInfo, error)
-	NetAddrsListen(context.Context) (peer.AddrInfo, error)
-	NetPubsubScores(context.Context) ([]api.PubsubScore, error)
-	NetAgentVersion(ctx context.Context, p peer.ID) (string, error)
-	NetPeerInfo(context.Context, peer.ID) (*api.ExtendedPeerInfo, error)
+	ID(context.Context) (peer.ID, error)                                 //perm:read
+	NetAutoNatStatus(ctx context.Context) (i api.NatInfo, err error)     //perm:read
+	NetPeers(context.Context) ([]peer.AddrInfo, error)                   //perm:read
+	NetAddrsListen(context.Context) (peer.AddrInfo, error)               //perm:read
+	NetPubsubScores(context.Context) ([]api.PubsubScore, error)          //perm:read
+	NetAgentVersion(ctx context.Context, p peer.ID) (string, error)      //perm:read
+	NetPeerInfo(context.Context, peer.ID) (*api.ExtendedPeerInfo, error) //perm:read
 }
 
 type LilyWatchConfig struct {
@@ -69,6 +94,7 @@ type LilyWatchConfig struct {
 	RestartOnCompletion bool
 	RestartDelay        time.Duration
 	Storage             string // name of storage system to use, may be empty
+	GasTraceLevel       string // level of detail recorded by the gastrace task, may be empty
 }
 
 type LilyWalkConfig struct {
@@ -81,6 +107,7 @@ type LilyWalkConfig struct {
 	RestartOnCompletion bool
 	RestartDelay        time.Duration
 	Storage             string // name of storage system to use, may be empty
+	GasTraceLevel       string // level of detail recorded by the gastrace task, may be empty
 }
 
 type LilyGapFindConfig struct {
@@ -91,6 +118,9 @@ type LilyGapFindConfig struct {
 	Name                string
 	To                  uint64
 	From                uint64
+	ToExpr              string   // height expression such as "head-10" that overrides To when set, may be empty
+	FromExpr            string   // height expression such as "head-2880" that overrides From when set, may be empty
+	Schedule            string   // cron expression on which to run the job, may be empty to run the job once immediately
 	Tasks               []string // name of tasks to fill gaps for
 }
 
@@ -102,5 +132,41 @@ type LilyGapFillConfig struct {
 	Name                string
 	To                  uint64
 	From                uint64
+	ToExpr              string   // height expression such as "head-10" that overrides To when set, may be empty
+	FromExpr            string   // height expression such as "head-2880" that overrides From when set, may be empty
+	Schedule            string   // cron expression on which to run the job, may be empty to run the job once immediately
 	Tasks               []string // name of tasks to fill gaps for
 }
+
+type LilyViewRefreshConfig struct {
+	RestartOnFailure    bool
+	RestartOnCompletion bool
+	RestartDelay        time.Duration
+	Storage             string // name of storage system to use, cannot be empty and must be Database storage.
+	Name                string
+	RefreshRate         time.Duration // how often to refresh the views, zero to refresh once each time the job runs
+	Schedule            string        // cron expression on which to run the job, may be empty to run the job once immediately
+}
+
+type LilySubscribeConfig struct {
+	JobName string   // name of the job whose models are streamed
+	Tables  []string // tables to stream, all tables if empty
+}
+
+type LilyIndexConfig struct {
+	TipSet        types.TipSetKey // tipset to index
+	Tasks         []string        // name of tasks to run
+	Storage       string          // name of storage to also persist the models to, may be empty
+	GasTraceLevel string          // level of detail recorded by the gastrace task, may be empty
+}
+
+type LilyIndexResult struct {
+	TipSet types.TipSetKey
+	Height int64
+
+	// Models are the models extracted from the tipset keyed by table name.
+	Models map[string][]interface{}
+
+	// Reports are the processing reports written by each task.
+	Reports []interface{}
+}
@@ -2,8 +2,12 @@ package lily
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/lily/lens/lily/modules"
@@ -21,10 +25,15 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/lily/chain"
+	"github.com/filecoin-project/lily/chain/actors/builtin"
+	"github.com/filecoin-project/lily/config"
 	"github.com/filecoin-project/lily/lens"
 	"github.com/filecoin-project/lily/lens/util"
 	"github.com/filecoin-project/lily/schedule"
 	"github.com/filecoin-project/lily/storage"
+	"github.com/filecoin-project/lily/tasks/gastrace"
+	"github.com/filecoin-project/lily/tasks/views"
+	"github.com/filecoin-project/lily/wait"
 )
 
 var _ LilyAPI = (*LilyNodeAPI)(nil)
@@ -40,6 +49,7 @@ type LilyNodeAPI struct {
 	Scheduler      *schedule.Scheduler
 	StorageCatalog *storage.Catalog
 	ExecMonitor    stmgr.ExecMonitor
+	Conf           *config.Conf
 }
 
 func (m *LilyNodeAPI) ChainGetTipSetAfterHeight(ctx context.Context, epoch abi.ChainEpoch, key types.TipSetKey) (*types.TipSet, error) {
@@ -56,6 +66,160 @@ func (m *LilyNodeAPI) Daemonized() bool {
 }
 
 func (m *LilyNodeAPI) LilyWatch(_ context.Context, cfg *LilyWatchConfig) (schedule.JobID, error) {
+	jc, err := m.watchJob(cfg)
+	if err != nil {
+		return schedule.InvalidJobID, err
+	}
+	// persist the job so it is resubmitted when the daemon restarts
+	jc.Config = cfg
+	return m.Scheduler.Submit(jc), nil
+}
+
+func (m *LilyNodeAPI) LilyWalk(_ context.Context, cfg *LilyWalkConfig) (schedule.JobID, error) {
+	jc, err := m.walkJob(cfg)
+	if err != nil {
+		return schedule.InvalidJobID, err
+	}
+	// persist the job so it is resubmitted when the daemon restarts
+	jc.Config = cfg
+	return m.Scheduler.Submit(jc), nil
+}
+
+func (m *LilyNodeAPI) LilyGapFind(_ context.Context, cfg *LilyGapFindConfig) (schedule.JobID, error) {
+	jc, err := m.gapFindJob(cfg)
+	if err != nil {
+		return schedule.InvalidJobID, err
+	}
+	// persist the job so it is resubmitted when the daemon restarts
+	jc.Config = cfg
+	return m.Scheduler.Submit(jc), nil
+}
+
+func (m *LilyNodeAPI) LilyGapFill(_ context.Context, cfg *LilyGapFillConfig) (schedule.JobID, error) {
+	jc, err := m.gapFillJob(cfg)
+	if err != nil {
+		return schedule.InvalidJobID, err
+	}
+	// persist the job so it is resubmitted when the daemon restarts
+	jc.Config = cfg
+	return m.Scheduler.Submit(jc), nil
+}
+
+func (m *LilyNodeAPI) LilyViewRefresh(_ context.Context, cfg *LilyViewRefreshConfig) (schedule.JobID, error) {
+	jc, err := m.viewRefreshJob(cfg)
+	if err != nil {
+		return schedule.InvalidJobID, err
+	}
+	// persist the job so it is resubmitted when the daemon restarts
+	jc.Config = cfg
+	return m.Scheduler.Submit(jc), nil
+}
+
+func (m *LilyNodeAPI) LilySubscribe(ctx context.Context, cfg *LilySubscribeConfig) (<-chan *storage.Batch, error) {
+	// job names are not unique so only a single watch may match
+	var watch *schedule.JobResult
+	jobs := m.Scheduler.Jobs()
+	for i := range jobs {
+		if jobs[i].Name != cfg.JobName || jobs[i].Type != "watch" {
+			continue
+		}
+		if watch != nil {
+			return nil, xerrors.Errorf("subscribe to job %q: more than one watch job has that name", cfg.JobName)
+		}
+		watch = &jobs[i]
+	}
+	if watch == nil {
+		return nil, xerrors.Errorf("subscribe to job %q: watch job not found", cfg.JobName)
+	}
+
+	ps, err := m.StorageCatalog.Publisher(watch.Params["storage"])
+	if err != nil {
+		return nil, xerrors.Errorf("subscribe to job %q: %w", cfg.JobName, err)
+	}
+	// the subscription ends when the client disconnects
+	return ps.Subscribe(ctx, cfg.JobName, cfg.Tables), nil
+}
+
+// LilyIndex runs the indexer over a single tipset and returns the models it extracts. The tipset's child on the
+// heaviest chain is used as the next tipset by tasks that need one, so the head of the chain cannot be indexed.
+func (m *LilyNodeAPI) LilyIndex(ctx context.Context, cfg *LilyIndexConfig) (*LilyIndexResult, error) {
+	gasTraceLevel, err := gastrace.ParseLevel(cfg.GasTraceLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, err := m.ChainGetTipSet(ctx, cfg.TipSet)
+	if err != nil {
+		return nil, xerrors.Errorf("get tipset: %w", err)
+	}
+
+	head, err := m.ChainHead(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get chain head: %w", err)
+	}
+	if ts.Height() >= head.Height() {
+		return nil, xerrors.Errorf("cannot index tipset at height %d: it has no child on the heaviest chain yet", ts.Height())
+	}
+
+	child, err := m.ChainGetTipSetAfterHeight(ctx, ts.Height()+1, head.Key())
+	if err != nil {
+		return nil, xerrors.Errorf("get child tipset: %w", err)
+	}
+	// receipts and executed messages are taken from the child so it must be built on the tipset being indexed
+	if child.Parents() != ts.Key() {
+		return nil, xerrors.Errorf("cannot index tipset %s: it is not on the heaviest chain", ts.Key())
+	}
+
+	mem := storage.NewMemStorageLatest()
+	rec := &recordingStorage{Storage: mem}
+
+	indexer, err := chain.NewTipSetIndexer(m, rec, 0, "index", cfg.Tasks, chain.GasTraceLevel(gasTraceLevel))
+	if err != nil {
+		return nil, err
+	}
+
+	// the indexer processes a tipset once it has seen the tipset after it
+	if err := indexer.TipSet(ctx, child); err != nil {
+		return nil, xerrors.Errorf("index: %w", err)
+	}
+	if err := indexer.TipSet(ctx, ts); err != nil {
+		return nil, xerrors.Errorf("index: %w", err)
+	}
+	// wait for the extracted models to be persisted
+	if err := indexer.Close(); err != nil {
+		return nil, xerrors.Errorf("close indexer: %w", err)
+	}
+	// the indexer only logs failures to persist, so check that every batch was recorded
+	if err := rec.err(); err != nil {
+		return nil, xerrors.Errorf("persist: %w", err)
+	}
+
+	if cfg.Storage != "" {
+		strg, err := m.StorageCatalog.Connect(ctx, cfg.Storage, storage.Metadata{JobName: "index"})
+		if err != nil {
+			return nil, err
+		}
+		if err := rec.replay(strg); err != nil {
+			return nil, xerrors.Errorf("persist to storage %q: %w", cfg.Storage, err)
+		}
+	}
+
+	res := &LilyIndexResult{
+		TipSet: ts.Key(),
+		Height: int64(ts.Height()),
+		Models: make(map[string][]interface{}, len(mem.Data)),
+	}
+	for table, models := range mem.Data {
+		if table == processingReportsTable {
+			res.Reports = models
+			continue
+		}
+		res.Models[table] = models
+	}
+	return res, nil
+}
+
+func (m *LilyNodeAPI) watchJob(cfg *LilyWatchConfig) (*schedule.JobConfig, error) {
 	// the context's passed to these methods live for the duration of the clients request, so make a new one.
 	ctx := context.Background()
 
@@ -63,16 +227,21 @@ func (m *LilyNodeAPI) LilyWatch(_ context.Context, cfg *LilyWatchConfig) (schedu
 		JobName: cfg.Name,
 	}
 
+	gasTraceLevel, err := gastrace.ParseLevel(cfg.GasTraceLevel)
+	if err != nil {
+		return nil, err
+	}
+
 	// create a database connection for this watch, ensure its pingable, and run migrations if needed/configured to.
 	strg, err := m.StorageCatalog.Connect(ctx, cfg.Storage, md)
 	if err != nil {
-		return schedule.InvalidJobID, err
+		return nil, err
 	}
 
 	// instantiate an indexer to extract block, message, and actor state data from observed tipsets and persists it to the storage.
-	indexer, err := chain.NewTipSetIndexer(m, strg, cfg.Window, cfg.Name, cfg.Tasks)
+	indexer, err := chain.NewTipSetIndexer(m, strg, cfg.Window, cfg.Name, cfg.Tasks, chain.GasTraceLevel(gasTraceLevel))
 	if err != nil {
-		return schedule.InvalidJobID, err
+		return nil, err
 	}
 
 	// HeadNotifier bridges between the event system and the watcher
@@ -83,7 +252,7 @@ func (m *LilyNodeAPI) LilyWatch(_ context.Context, cfg *LilyWatchConfig) (schedu
 	// get the current head and set it on the tipset cache (mimic chain.watcher behaviour)
 	head, err := m.ChainModuleAPI.ChainHead(ctx)
 	if err != nil {
-		return schedule.InvalidJobID, err
+		return nil, err
 	}
 
 	// Won't block since we are using non-zero buffer size in head notifier
@@ -93,12 +262,13 @@ func (m *LilyNodeAPI) LilyWatch(_ context.Context, cfg *LilyWatchConfig) (schedu
 
 	// Hook up the notifier to the event system
 	if err := m.Events.Observe(obs); err != nil {
-		return schedule.InvalidJobID, err
+		return nil, err
 	}
 
-	id := m.Scheduler.Submit(&schedule.JobConfig{
-		Name: cfg.Name,
-		Type: "watch",
+	return &schedule.JobConfig{
+		Name:     cfg.Name,
+		Type:     "watch",
+		Priority: schedule.PriorityHigh,
 		Params: map[string]string{
 			"window":     cfg.Window.String(),
 			"confidence": fmt.Sprintf("%d", cfg.Confidence),
@@ -109,12 +279,10 @@ func (m *LilyNodeAPI) LilyWatch(_ context.Context, cfg *LilyWatchConfig) (schedu
 		RestartOnFailure:    cfg.RestartOnFailure,
 		RestartOnCompletion: cfg.RestartOnCompletion,
 		RestartDelay:        cfg.RestartDelay,
-	})
-
-	return id, nil
+	}, nil
 }
 
-func (m *LilyNodeAPI) LilyWalk(_ context.Context, cfg *LilyWalkConfig) (schedule.JobID, error) {
+func (m *LilyNodeAPI) walkJob(cfg *LilyWalkConfig) (*schedule.JobConfig, error) {
 	// the context's passed to these methods live for the duration of the clients request, so make a new one.
 	ctx := context.Background()
 
@@ -122,21 +290,27 @@ func (m *LilyNodeAPI) LilyWalk(_ context.Context, cfg *LilyWalkConfig) (schedule
 		JobName: cfg.Name,
 	}
 
+	gasTraceLevel, err := gastrace.ParseLevel(cfg.GasTraceLevel)
+	if err != nil {
+		return nil, err
+	}
+
 	// create a database connection for this watch, ensure its pingable, and run migrations if needed/configured to.
 	strg, err := m.StorageCatalog.Connect(ctx, cfg.Storage, md)
 	if err != nil {
-		return schedule.InvalidJobID, err
+		return nil, err
 	}
 
 	// instantiate an indexer to extract block, message, and actor state data from observed tipsets and persists it to the storage.
-	indexer, err := chain.NewTipSetIndexer(m, strg, cfg.Window, cfg.Name, cfg.Tasks)
+	indexer, err := chain.NewTipSetIndexer(m, strg, cfg.Window, cfg.Name, cfg.Tasks, chain.GasTraceLevel(gasTraceLevel))
 	if err != nil {
-		return schedule.InvalidJobID, err
+		return nil, err
 	}
 
-	id := m.Scheduler.Submit(&schedule.JobConfig{
-		Name: cfg.Name,
-		Type: "walk",
+	return &schedule.JobConfig{
+		Name:     cfg.Name,
+		Type:     "walk",
+		Priority: schedule.PriorityLow,
 		Params: map[string]string{
 			"window":    cfg.Window.String(),
 			"minHeight": fmt.Sprintf("%d", cfg.From),
@@ -148,12 +322,10 @@ func (m *LilyNodeAPI) LilyWalk(_ context.Context, cfg *LilyWalkConfig) (schedule
 		RestartOnFailure:    cfg.RestartOnFailure,
 		RestartOnCompletion: cfg.RestartOnCompletion,
 		RestartDelay:        cfg.RestartDelay,
-	})
-
-	return id, nil
+	}, nil
 }
 
-func (m *LilyNodeAPI) LilyGapFind(_ context.Context, cfg *LilyGapFindConfig) (schedule.JobID, error) {
+func (m *LilyNodeAPI) gapFindJob(cfg *LilyGapFindConfig) (*schedule.JobConfig, error) {
 	// the context's passed to these methods live for the duration of the clients request, so make a new one.
 	ctx := context.Background()
 
@@ -164,28 +336,45 @@ func (m *LilyNodeAPI) LilyGapFind(_ context.Context, cfg *LilyGapFindConfig) (sc
 	// create a database connection for this watch, ensure its pingable, and run migrations if needed/configured to.
 	db, err := m.StorageCatalog.ConnectAsDatabase(ctx, cfg.Storage, md)
 	if err != nil {
-		return schedule.InvalidJobID, err
+		return nil, err
 	}
 
-	id := m.Scheduler.Submit(&schedule.JobConfig{
-		Name:  cfg.Name,
-		Type:  "Find",
-		Tasks: cfg.Tasks,
+	min, max, err := parseRange(cfg.From, cfg.FromExpr, cfg.To, cfg.ToExpr)
+	if err != nil {
+		return nil, err
+	}
+
+	sched, err := parseSchedule(cfg.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule.JobConfig{
+		Name:     cfg.Name,
+		Type:     "Find",
+		Priority: schedule.PriorityNormal,
+		Tasks:    cfg.Tasks,
 		Params: map[string]string{
-			"minHeight": fmt.Sprintf("%d", cfg.From),
-			"maxHeight": fmt.Sprintf("%d", cfg.To),
+			"minHeight": min.String(),
+			"maxHeight": max.String(),
 			"storage":   cfg.Storage,
 		},
-		Job:                 chain.NewGapIndexer(m, db, cfg.Name, cfg.From, cfg.To, cfg.Tasks),
+		Job: &rangeJob{
+			head: m.headHeight,
+			min:  min,
+			max:  max,
+			newJob: func(from, to uint64) schedule.Job {
+				return chain.NewGapIndexer(m, db, cfg.Name, from, to, cfg.Tasks)
+			},
+		},
 		RestartOnFailure:    cfg.RestartOnFailure,
 		RestartOnCompletion: cfg.RestartOnCompletion,
 		RestartDelay:        cfg.RestartDelay,
-	})
-
-	return id, nil
+		Schedule:            sched,
+	}, nil
 }
 
-func (m *LilyNodeAPI) LilyGapFill(_ context.Context, cfg *LilyGapFillConfig) (schedule.JobID, error) {
+func (m *LilyNodeAPI) gapFillJob(cfg *LilyGapFillConfig) (*schedule.JobConfig, error) {
 	// the context's passed to these methods live for the duration of the clients request, so make a new one.
 	ctx := context.Background()
 
@@ -196,25 +385,212 @@ func (m *LilyNodeAPI) LilyGapFill(_ context.Context, cfg *LilyGapFillConfig) (sc
 	// create a database connection for this watch, ensure its pingable, and run migrations if needed/configured to.
 	db, err := m.StorageCatalog.ConnectAsDatabase(ctx, cfg.Storage, md)
 	if err != nil {
-		return schedule.InvalidJobID, err
+		return nil, err
+	}
+
+	min, max, err := parseRange(cfg.From, cfg.FromExpr, cfg.To, cfg.ToExpr)
+	if err != nil {
+		return nil, err
 	}
 
-	id := m.Scheduler.Submit(&schedule.JobConfig{
-		Name: cfg.Name,
-		Type: "Fill",
+	sched, err := parseSchedule(cfg.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule.JobConfig{
+		Name:     cfg.Name,
+		Type:     "Fill",
+		Priority: schedule.PriorityNormal,
 		Params: map[string]string{
-			"minHeight": fmt.Sprintf("%d", cfg.From),
-			"maxHeight": fmt.Sprintf("%d", cfg.To),
+			"minHeight": min.String(),
+			"maxHeight": max.String(),
 			"storage":   cfg.Storage,
 		},
-		Tasks:               cfg.Tasks,
-		Job:                 chain.NewGapFiller(m, db, cfg.Name, cfg.From, cfg.To, cfg.Tasks),
+		Tasks: cfg.Tasks,
+		Job: &rangeJob{
+			head: m.headHeight,
+			min:  min,
+			max:  max,
+			newJob: func(from, to uint64) schedule.Job {
+				return chain.NewGapFiller(m, db, cfg.Name, from, to, cfg.Tasks)
+			},
+		},
 		RestartOnFailure:    cfg.RestartOnFailure,
 		RestartOnCompletion: cfg.RestartOnCompletion,
 		RestartDelay:        cfg.RestartDelay,
-	})
+		Schedule:            sched,
+	}, nil
+}
 
-	return id, nil
+func (m *LilyNodeAPI) viewRefreshJob(cfg *LilyViewRefreshConfig) (*schedule.JobConfig, error) {
+	// the context's passed to these methods live for the duration of the clients request, so make a new one.
+	ctx := context.Background()
+
+	db, err := m.StorageCatalog.ConnectAsDatabase(ctx, cfg.Storage, storage.Metadata{JobName: cfg.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	sched, err := parseSchedule(cfg.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule.JobConfig{
+		Name:     cfg.Name,
+		Type:     "viewrefresh",
+		Priority: schedule.PriorityNormal,
+		Params: map[string]string{
+			"refreshRate": cfg.RefreshRate.String(),
+			"storage":     cfg.Storage,
+		},
+		Job:                 views.NewChainVisRefresher(db, cfg.RefreshRate),
+		RestartOnFailure:    cfg.RestartOnFailure,
+		RestartOnCompletion: cfg.RestartOnCompletion,
+		RestartDelay:        cfg.RestartDelay,
+		Schedule:            sched,
+	}, nil
+}
+
+// SubmitConfiguredJobs waits until the daemon has synced with the chain and then submits the jobs declared in its
+// config. Declared jobs are not persisted since they are submitted each time the daemon starts. A job that cannot be
+// created is logged and does not prevent the remaining jobs from being submitted.
+func (m *LilyNodeAPI) SubmitConfiguredJobs(ctx context.Context) error {
+	if m.Conf == nil {
+		return nil
+	}
+	jobs := m.Conf.Jobs
+	if len(jobs.Watch)+len(jobs.Walk)+len(jobs.GapFind)+len(jobs.GapFill)+len(jobs.ViewRefresh) == 0 {
+		return nil
+	}
+
+	log.Info("waiting for chain sync before submitting configured jobs")
+	if err := wait.RepeatUntil(ctx, time.Duration(builtin.EpochDurationSeconds)*time.Second, m.synced); err != nil {
+		return xerrors.Errorf("wait for sync: %w", err)
+	}
+
+	submit := func(kind, name string, jc *schedule.JobConfig, err error) {
+		if err != nil {
+			log.Errorw("failed to create configured job", "type", kind, "name", name, "error", err)
+			return
+		}
+		id := m.Scheduler.Submit(jc)
+		log.Infow("submitted configured job", "id", id, "type", kind, "name", name)
+	}
+
+	for _, name := range sortedKeys(jobs.Watch) {
+		j := jobs.Watch[name]
+		jc, err := m.watchJob(&LilyWatchConfig{
+			Name:                name,
+			Tasks:               j.Tasks,
+			Window:              time.Duration(j.Window),
+			Confidence:          j.Confidence,
+			RestartOnFailure:    j.RestartOnFailure,
+			RestartOnCompletion: j.RestartOnCompletion,
+			RestartDelay:        time.Duration(j.RestartDelay),
+			Storage:             j.Storage,
+			GasTraceLevel:       j.GasTraceLevel,
+		})
+		submit("watch", name, jc, err)
+	}
+	for _, name := range sortedKeys(jobs.Walk) {
+		j := jobs.Walk[name]
+		jc, err := m.walkJob(&LilyWalkConfig{
+			From:                j.From,
+			To:                  j.To,
+			Name:                name,
+			Tasks:               j.Tasks,
+			Window:              time.Duration(j.Window),
+			RestartOnFailure:    j.RestartOnFailure,
+			RestartOnCompletion: j.RestartOnCompletion,
+			RestartDelay:        time.Duration(j.RestartDelay),
+			Storage:             j.Storage,
+			GasTraceLevel:       j.GasTraceLevel,
+		})
+		submit("walk", name, jc, err)
+	}
+	for _, name := range sortedKeys(jobs.GapFind) {
+		j := jobs.GapFind[name]
+		jc, err := m.gapFindJob(&LilyGapFindConfig{
+			RestartOnFailure:    j.RestartOnFailure,
+			RestartOnCompletion: j.RestartOnCompletion,
+			RestartDelay:        time.Duration(j.RestartDelay),
+			Storage:             j.Storage,
+			Name:                name,
+			To:                  j.To,
+			From:                j.From,
+			ToExpr:              j.ToExpr,
+			FromExpr:            j.FromExpr,
+			Schedule:            j.Schedule,
+			Tasks:               j.Tasks,
+		})
+		submit("gap find", name, jc, err)
+	}
+	for _, name := range sortedKeys(jobs.GapFill) {
+		j := jobs.GapFill[name]
+		jc, err := m.gapFillJob(&LilyGapFillConfig{
+			RestartOnFailure:    j.RestartOnFailure,
+			RestartOnCompletion: j.RestartOnCompletion,
+			RestartDelay:        time.Duration(j.RestartDelay),
+			Storage:             j.Storage,
+			Name:                name,
+			To:                  j.To,
+			From:                j.From,
+			ToExpr:              j.ToExpr,
+			FromExpr:            j.FromExpr,
+			Schedule:            j.Schedule,
+			Tasks:               j.Tasks,
+		})
+		submit("gap fill", name, jc, err)
+	}
+	for _, name := range sortedKeys(jobs.ViewRefresh) {
+		j := jobs.ViewRefresh[name]
+		jc, err := m.viewRefreshJob(&LilyViewRefreshConfig{
+			RestartOnFailure:    j.RestartOnFailure,
+			RestartOnCompletion: j.RestartOnCompletion,
+			RestartDelay:        time.Duration(j.RestartDelay),
+			Storage:             j.Storage,
+			Name:                name,
+			RefreshRate:         time.Duration(j.RefreshRate),
+			Schedule:            j.Schedule,
+		})
+		submit("view refresh", name, jc, err)
+	}
+
+	return nil
+}
+
+// headHeight returns the height of the head of the chain.
+func (m *LilyNodeAPI) headHeight(ctx context.Context) (int64, error) {
+	head, err := m.ChainModuleAPI.ChainHead(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int64(head.Height()), nil
+}
+
+// syncedEpochs is the number of epochs the head of the chain may be behind the current time for the daemon to be
+// considered synced, which allows for late blocks and null rounds.
+const syncedEpochs = 5
+
+// synced reports whether the head of the chain is within a few epochs of the current time.
+func (m *LilyNodeAPI) synced(ctx context.Context) (bool, error) {
+	head, err := m.ChainModuleAPI.ChainHead(ctx)
+	if err != nil {
+		return false, err
+	}
+	return time.Now().Unix()-int64(head.MinTimestamp()) < syncedEpochs*int64(builtin.EpochDurationSeconds), nil
+}
+
+// sortedKeys returns the keys of a map of job configs in order so that jobs are submitted deterministically.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 func (m *LilyNodeAPI) LilyJobStart(_ context.Context, ID schedule.JobID) error {
@@ -235,6 +611,98 @@ func (m *LilyNodeAPI) LilyJobList(_ context.Context) ([]schedule.JobResult, erro
 	return m.Scheduler.Jobs(), nil
 }
 
+func (m *LilyNodeAPI) LilyJobForget(_ context.Context, ID schedule.JobID) error {
+	if err := m.Scheduler.ForgetJob(ID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *LilyNodeAPI) LilyJobRemove(_ context.Context, ID schedule.JobID) error {
+	if err := m.Scheduler.RemoveJob(ID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *LilyNodeAPI) LilyJobGet(_ context.Context, ID schedule.JobID) (*schedule.JobDetail, error) {
+	return m.Scheduler.Job(ID)
+}
+
+func (m *LilyNodeAPI) LilyJobLogs(_ context.Context, ID schedule.JobID, n int) ([]schedule.LogLine, error) {
+	return m.Scheduler.JobLogs(ID, n)
+}
+
+func (m *LilyNodeAPI) LilyJobWait(ctx context.Context, ID schedule.JobID) (*schedule.JobResult, error) {
+	return m.Scheduler.WaitJob(ctx, ID)
+}
+
+// RestoreJobs resubmits the jobs persisted by a previous run of the daemon. Walks are resumed from the lowest height
+// they reached. Jobs that cannot be resubmitted are logged and kept so they can be retried on the next restart or
+// forgotten.
+func (m *LilyNodeAPI) RestoreJobs(ctx context.Context) error {
+	specs, err := m.Scheduler.PersistedJobs()
+	if err != nil {
+		return xerrors.Errorf("list persisted jobs: %w", err)
+	}
+
+	for _, spec := range specs {
+		id, err := m.restoreJob(ctx, spec)
+		if err != nil {
+			log.Errorw("failed to restore job", "id", spec.ID, "name", spec.Name, "type", spec.Type, "error", err)
+			continue
+		}
+
+		// the resubmitted job has been persisted under its new ID
+		if err := m.Scheduler.ForgetJob(spec.ID); err != nil {
+			log.Errorw("failed to forget restored job", "id", spec.ID, "name", spec.Name, "error", err)
+		}
+		log.Infow("restored job", "id", id, "previous_id", spec.ID, "name", spec.Name, "type", spec.Type)
+	}
+	return nil
+}
+
+func (m *LilyNodeAPI) restoreJob(ctx context.Context, spec *schedule.JobSpec) (schedule.JobID, error) {
+	switch spec.Type {
+	case "watch":
+		cfg := new(LilyWatchConfig)
+		if err := json.Unmarshal(spec.Config, cfg); err != nil {
+			return schedule.InvalidJobID, xerrors.Errorf("unmarshal config: %w", err)
+		}
+		return m.LilyWatch(ctx, cfg)
+	case "walk":
+		cfg := new(LilyWalkConfig)
+		if err := json.Unmarshal(spec.Config, cfg); err != nil {
+			return schedule.InvalidJobID, xerrors.Errorf("unmarshal config: %w", err)
+		}
+		// resume from the lowest height reached since walks move from the maximum height towards the minimum
+		if spec.Progress != nil && *spec.Progress < cfg.To {
+			cfg.To = *spec.Progress
+		}
+		return m.LilyWalk(ctx, cfg)
+	case "Find":
+		cfg := new(LilyGapFindConfig)
+		if err := json.Unmarshal(spec.Config, cfg); err != nil {
+			return schedule.InvalidJobID, xerrors.Errorf("unmarshal config: %w", err)
+		}
+		return m.LilyGapFind(ctx, cfg)
+	case "Fill":
+		cfg := new(LilyGapFillConfig)
+		if err := json.Unmarshal(spec.Config, cfg); err != nil {
+			return schedule.InvalidJobID, xerrors.Errorf("unmarshal config: %w", err)
+		}
+		return m.LilyGapFill(ctx, cfg)
+	case "viewrefresh":
+		cfg := new(LilyViewRefreshConfig)
+		if err := json.Unmarshal(spec.Config, cfg); err != nil {
+			return schedule.InvalidJobID, xerrors.Errorf("unmarshal config: %w", err)
+		}
+		return m.LilyViewRefresh(ctx, cfg)
+	default:
+		return schedule.InvalidJobID, xerrors.Errorf("unknown job type: %s", spec.Type)
+	}
+}
+
 func (m *LilyNodeAPI) GetExecutedAndBlockMessagesForTipset(ctx context.Context, ts, pts *types.TipSet) (*lens.TipSetMessages, error) {
 	return util.GetExecutedAndBlockMessagesForTipset(ctx, m.ChainAPI.Chain, ts, pts)
 }
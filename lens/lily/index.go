@@ -0,0 +1,59 @@
+package lily
+
+import (
+	"context"
+	"sync"
+
+	"github.com/filecoin-project/lily/model"
+)
+
+// processingReportsTable is the table holding the processing reports written by each task.
+const processingReportsTable = "visor_processing_reports"
+
+// recordingStorage records each batch it persists so that the batches can be persisted to another storage later. It
+// also keeps the first error returned when persisting a batch.
+type recordingStorage struct {
+	model.Storage
+
+	mu       sync.Mutex
+	batches  []recordedBatch
+	firstErr error
+}
+
+type recordedBatch struct {
+	ctx context.Context // carries the tipset timestamps used by storages that enrich rows with them
+	ps  []model.Persistable
+}
+
+func (r *recordingStorage) PersistBatch(ctx context.Context, ps ...model.Persistable) error {
+	err := r.Storage.PersistBatch(ctx, ps...)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		if r.firstErr == nil {
+			r.firstErr = err
+		}
+		return err
+	}
+	r.batches = append(r.batches, recordedBatch{ctx: ctx, ps: ps})
+	return nil
+}
+
+// err returns the first error returned when persisting a batch, if any.
+func (r *recordingStorage) err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.firstErr
+}
+
+// replay persists the recorded batches to strg.
+func (r *recordingStorage) replay(strg model.Storage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range r.batches {
+		if err := strg.PersistBatch(b.ctx, b.ps...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
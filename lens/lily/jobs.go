@@ -0,0 +1,79 @@
+package lily
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/schedule"
+)
+
+// parseRange returns the heights of a job that covers a range of heights. A non-empty expression takes precedence
+// over the corresponding absolute height.
+func parseRange(from uint64, fromExpr string, to uint64, toExpr string) (schedule.Height, schedule.Height, error) {
+	min := schedule.Height{Offset: int64(from)}
+	max := schedule.Height{Offset: int64(to)}
+	var err error
+	if fromExpr != "" {
+		if min, err = schedule.ParseHeight(fromExpr); err != nil {
+			return min, max, xerrors.Errorf("from: %w", err)
+		}
+	}
+	if toExpr != "" {
+		if max, err = schedule.ParseHeight(toExpr); err != nil {
+			return min, max, xerrors.Errorf("to: %w", err)
+		}
+	}
+	return min, max, nil
+}
+
+// parseSchedule returns the schedule described by a cron expression, nil if the expression is empty.
+func parseSchedule(expr string) (*schedule.CronSchedule, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	return schedule.ParseCron(expr)
+}
+
+// rangeJob runs a job over a range of heights that may be relative to the head of the chain. The range is resolved
+// each time the job is run so that a scheduled job covers a range that moves with the chain.
+type rangeJob struct {
+	head     func(context.Context) (int64, error)
+	min, max schedule.Height
+	newJob   func(min, max uint64) schedule.Job
+
+	mu      sync.Mutex
+	current schedule.Job
+}
+
+func (j *rangeJob) Run(ctx context.Context) error {
+	min, max := j.min.Offset, j.max.Offset
+	if j.min.Relative || j.max.Relative {
+		head, err := j.head(ctx)
+		if err != nil {
+			return xerrors.Errorf("get chain head: %w", err)
+		}
+		min, max = j.min.Resolve(head), j.max.Resolve(head)
+	}
+	if min > max {
+		return xerrors.Errorf("from height %d (%s) is greater than to height %d (%s)", min, j.min, max, j.max)
+	}
+
+	job := j.newJob(uint64(min), uint64(max))
+	j.mu.Lock()
+	j.current = job
+	j.mu.Unlock()
+
+	return job.Run(ctx)
+}
+
+// ReportProgress implements schedule.ProgressReporter for the most recent run of the job.
+func (j *rangeJob) ReportProgress() *schedule.JobProgress {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if r, ok := j.current.(schedule.ProgressReporter); ok {
+		return r.ReportProgress()
+	}
+	return nil
+}
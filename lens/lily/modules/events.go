@@ -1,12 +1,17 @@
 package modules
 
 import (
+	"context"
+
 	"github.com/filecoin-project/lotus/chain/events"
 	"github.com/filecoin-project/lotus/node/impl/full"
 	"github.com/filecoin-project/lotus/node/modules/helpers"
 	"go.uber.org/fx"
+	"golang.org/x/xerrors"
 
+	"github.com/filecoin-project/lily/chain"
 	"github.com/filecoin-project/lily/config"
+	"github.com/filecoin-project/lily/schedule"
 	"github.com/filecoin-project/lily/storage"
 )
 
@@ -23,11 +28,82 @@ func NewEvents(mctx helpers.MetricsCtx, lc fx.Lifecycle, chainAPI full.ChainModu
 }
 
 func NewStorageCatalog(mctx helpers.MetricsCtx, lc fx.Lifecycle, cfg *config.Conf) (*storage.Catalog, error) {
-	return storage.NewCatalog(cfg.Storage)
+	c, err := storage.NewCatalog(cfg.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	// connections made by the catalog are kept for the lifetime of the daemon
+	ctx := helpers.LifecycleCtx(mctx, lc)
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			c.RefreshReferenceData(ctx)
+			return nil
+		},
+	})
+	return c, nil
 }
 
 func LoadConf(path string) func(mctx helpers.MetricsCtx, lc fx.Lifecycle) (*config.Conf, error) {
 	return func(mctx helpers.MetricsCtx, lc fx.Lifecycle) (*config.Conf, error) {
-		return config.FromFile(path)
+		cfg, err := config.FromFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// reject jobs that could not be submitted before the daemon starts syncing
+		if err := cfg.ValidateJobs(chain.KnownTasks()); err != nil {
+			return nil, xerrors.Errorf("invalid jobs config: %w", err)
+		}
+		if err := validateJobSchedules(cfg.Jobs); err != nil {
+			return nil, xerrors.Errorf("invalid jobs config: %w", err)
+		}
+		return cfg, nil
+	}
+}
+
+// validateJobSchedules checks the cron expressions and height expressions of declared jobs.
+func validateJobSchedules(jobs config.JobsConf) error {
+	checkSchedule := func(kind, name, expr string) error {
+		if expr == "" {
+			return nil
+		}
+		if _, err := schedule.ParseCron(expr); err != nil {
+			return xerrors.Errorf("%s job %q: %w", kind, name, err)
+		}
+		return nil
+	}
+	checkHeight := func(kind, name, expr string) error {
+		if expr == "" {
+			return nil
+		}
+		if _, err := schedule.ParseHeight(expr); err != nil {
+			return xerrors.Errorf("%s job %q: %w", kind, name, err)
+		}
+		return nil
+	}
+
+	gaps := map[string]map[string]config.GapJobConf{
+		"gap find": jobs.GapFind,
+		"gap fill": jobs.GapFill,
+	}
+	for kind, confs := range gaps {
+		for name, j := range confs {
+			if err := checkSchedule(kind, name, j.Schedule); err != nil {
+				return err
+			}
+			if err := checkHeight(kind, name, j.FromExpr); err != nil {
+				return err
+			}
+			if err := checkHeight(kind, name, j.ToExpr); err != nil {
+				return err
+			}
+		}
+	}
+	for name, j := range jobs.ViewRefresh {
+		if err := checkSchedule("view refresh", name, j.Schedule); err != nil {
+			return err
+		}
 	}
+	return nil
 }
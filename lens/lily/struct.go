@@ -3,6 +3,7 @@ package lily
 import (
 	"context"
 
+	"github.com/filecoin-project/go-jsonrpc/auth"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/lotus/api"
 	"github.com/filecoin-project/lotus/api/v0api"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/filecoin-project/lily/lens"
 	"github.com/filecoin-project/lily/schedule"
+	"github.com/filecoin-project/lily/storage"
 )
 
 var log = logging.Logger("lily/lens/lily")
@@ -26,20 +28,33 @@ type LilyAPIStruct struct {
 	v0api.CommonStruct
 
 	Internal struct {
+		AuthVerify func(ctx context.Context, token string) ([]auth.Permission, error) `perm:"read"`
+		AuthNew    func(ctx context.Context, perms []auth.Permission) ([]byte, error) `perm:"admin"`
+
 		Store                                func() adt.Store                                                                  `perm:"read"`
 		GetExecutedAndBlockMessagesForTipset func(context.Context, *types.TipSet, *types.TipSet) (*lens.TipSetMessages, error) `perm:"read"`
 
-		LilyWatch func(context.Context, *LilyWatchConfig) (schedule.JobID, error) `perm:"read"`
-		LilyWalk  func(context.Context, *LilyWalkConfig) (schedule.JobID, error)  `perm:"read"`
+		LilyWatch func(context.Context, *LilyWatchConfig) (schedule.JobID, error) `perm:"write"`
+		LilyWalk  func(context.Context, *LilyWalkConfig) (schedule.JobID, error)  `perm:"write"`
+
+		LilyJobStart  func(ctx context.Context, ID schedule.JobID) error                              `perm:"job"`
+		LilyJobStop   func(ctx context.Context, ID schedule.JobID) error                              `perm:"job"`
+		LilyJobList   func(ctx context.Context) ([]schedule.JobResult, error)                         `perm:"read"`
+		LilyJobForget func(ctx context.Context, ID schedule.JobID) error                              `perm:"job"`
+		LilyJobRemove func(ctx context.Context, ID schedule.JobID) error                              `perm:"job"`
+		LilyJobGet    func(ctx context.Context, ID schedule.JobID) (*schedule.JobDetail, error)       `perm:"read"`
+		LilyJobLogs   func(ctx context.Context, ID schedule.JobID, n int) ([]schedule.LogLine, error) `perm:"read"`
+		LilyJobWait   func(ctx context.Context, ID schedule.JobID) (*schedule.JobResult, error)       `perm:"read"`
+
+		LilyGapFind func(ctx context.Context, cfg *LilyGapFindConfig) (schedule.JobID, error) `perm:"write"`
+		LilyGapFill func(ctx context.Context, cfg *LilyGapFillConfig) (schedule.JobID, error) `perm:"write"`
 
-		LilyJobStart func(ctx context.Context, ID schedule.JobID) error      `perm:"read"`
-		LilyJobStop  func(ctx context.Context, ID schedule.JobID) error      `perm:"read"`
-		LilyJobList  func(ctx context.Context) ([]schedule.JobResult, error) `perm:"read"`
+		LilyViewRefresh func(ctx context.Context, cfg *LilyViewRefreshConfig) (schedule.JobID, error) `perm:"write"`
 
-		LilyGapFind func(ctx context.Context, cfg *LilyGapFindConfig) (schedule.JobID, error) `perm:"read"`
-		LilyGapFill func(ctx context.Context, cfg *LilyGapFillConfig) (schedule.JobID, error) `perm:"read"`
+		LilySubscribe func(ctx context.Context, cfg *LilySubscribeConfig) (<-chan *storage.Batch, error) `perm:"read"`
+		LilyIndex     func(ctx context.Context, cfg *LilyIndexConfig) (*LilyIndexResult, error)          `perm:"write"`
 
-		Shutdown func(context.Context) error `perm:"read"`
+		Shutdown func(context.Context) error `perm:"admin"`
 
 		SyncState func(ctx context.Context) (*api.SyncState, error) `perm:"read"`
 
@@ -54,8 +69,8 @@ type LilyAPIStruct struct {
 		ChainGetParentMessages    func(context.Context, cid.Cid) ([]api.Message, error)                         `perm:"read"`
 		ChainGetTipSetAfterHeight func(context.Context, abi.ChainEpoch, types.TipSetKey) (*types.TipSet, error) `perm:"read"`
 
-		LogList     func(context.Context) ([]string, error)     `perm:"read"`
-		LogSetLevel func(context.Context, string, string) error `perm:"read"`
+		LogList     func(context.Context) ([]string, error)     `perm:"write"`
+		LogSetLevel func(context.Context, string, string) error `perm:"write"`
 
 		ID               func(context.Context) (peer.ID, error)                        `perm:"read"`
 		NetAutoNatStatus func(context.Context) (api.NatInfo, error)                    `perm:"read"`
@@ -67,6 +82,14 @@ type LilyAPIStruct struct {
 	}
 }
 
+func (s *LilyAPIStruct) AuthVerify(ctx context.Context, token string) ([]auth.Permission, error) {
+	return s.Internal.AuthVerify(ctx, token)
+}
+
+func (s *LilyAPIStruct) AuthNew(ctx context.Context, perms []auth.Permission) ([]byte, error) {
+	return s.Internal.AuthNew(ctx, perms)
+}
+
 func (s *LilyAPIStruct) ChainGetTipSetAfterHeight(ctx context.Context, epoch abi.ChainEpoch, key types.TipSetKey) (*types.TipSet, error) {
 	return s.Internal.ChainGetTipSetAfterHeight(ctx, epoch, key)
 }
@@ -127,6 +150,26 @@ func (s *LilyAPIStruct) LilyJobList(ctx context.Context) ([]schedule.JobResult,
 	return s.Internal.LilyJobList(ctx)
 }
 
+func (s *LilyAPIStruct) LilyJobForget(ctx context.Context, ID schedule.JobID) error {
+	return s.Internal.LilyJobForget(ctx, ID)
+}
+
+func (s *LilyAPIStruct) LilyJobRemove(ctx context.Context, ID schedule.JobID) error {
+	return s.Internal.LilyJobRemove(ctx, ID)
+}
+
+func (s *LilyAPIStruct) LilyJobGet(ctx context.Context, ID schedule.JobID) (*schedule.JobDetail, error) {
+	return s.Internal.LilyJobGet(ctx, ID)
+}
+
+func (s *LilyAPIStruct) LilyJobLogs(ctx context.Context, ID schedule.JobID, n int) ([]schedule.LogLine, error) {
+	return s.Internal.LilyJobLogs(ctx, ID, n)
+}
+
+func (s *LilyAPIStruct) LilyJobWait(ctx context.Context, ID schedule.JobID) (*schedule.JobResult, error) {
+	return s.Internal.LilyJobWait(ctx, ID)
+}
+
 func (s *LilyAPIStruct) LilyGapFind(ctx context.Context, cfg *LilyGapFindConfig) (schedule.JobID, error) {
 	return s.Internal.LilyGapFind(ctx, cfg)
 }
@@ -135,6 +178,18 @@ func (s *LilyAPIStruct) LilyGapFill(ctx context.Context, cfg *LilyGapFillConfig)
 	return s.Internal.LilyGapFill(ctx, cfg)
 }
 
+func (s *LilyAPIStruct) LilyViewRefresh(ctx context.Context, cfg *LilyViewRefreshConfig) (schedule.JobID, error) {
+	return s.Internal.LilyViewRefresh(ctx, cfg)
+}
+
+func (s *LilyAPIStruct) LilySubscribe(ctx context.Context, cfg *LilySubscribeConfig) (<-chan *storage.Batch, error) {
+	return s.Internal.LilySubscribe(ctx, cfg)
+}
+
+func (s *LilyAPIStruct) LilyIndex(ctx context.Context, cfg *LilyIndexConfig) (*LilyIndexResult, error) {
+	return s.Internal.LilyIndex(ctx, cfg)
+}
+
 func (s *LilyAPIStruct) Shutdown(ctx context.Context) error {
 	return s.Internal.Shutdown(ctx)
 }
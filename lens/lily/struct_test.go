@@ -0,0 +1,48 @@
+package lily
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPermTags checks that the perm tag of each method of LilyAPIStruct matches the perm annotation of the method in
+// the LilyAPI interface.
+func TestPermTags(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "api.go", nil, parser.ParseComments)
+	require.NoError(t, err)
+
+	annotated := map[string]string{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		ts, ok := n.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != "LilyAPI" {
+			return true
+		}
+		for _, m := range ts.Type.(*ast.InterfaceType).Methods.List {
+			require.Len(t, m.Names, 1)
+			name := m.Names[0].Name
+			require.NotNil(t, m.Comment, "method %s has no perm annotation", name)
+			perm := strings.TrimSpace(m.Comment.Text())
+			require.True(t, strings.HasPrefix(perm, "perm:"), "method %s has no perm annotation", name)
+			annotated[name] = strings.TrimPrefix(perm, "perm:")
+		}
+		return false
+	})
+	require.NotEmpty(t, annotated)
+
+	internal := reflect.TypeOf(LilyAPIStruct{}.Internal)
+	for name, perm := range annotated {
+		field, ok := internal.FieldByName(name)
+		if !assert.True(t, ok, "method %s is missing from LilyAPIStruct.Internal", name) {
+			continue
+		}
+		assert.Equal(t, perm, field.Tag.Get("perm"), "perm tag of %s", name)
+	}
+}
@@ -16,6 +16,13 @@ var DefaultNetwork = NewNetwork(stmgr.DefaultUpgradeSchedule(), build.NewestNetw
 type Network struct {
 	networkVersions []versionSpec
 	latestVersion   network.Version
+	upgrades        []Upgrade
+}
+
+// Upgrade is a scheduled upgrade of the network.
+type Upgrade struct {
+	Height  abi.ChainEpoch  // last epoch before the upgrade takes effect, negative if the upgrade is disabled
+	Network network.Version // network version after the upgrade
 }
 
 type versionSpec struct {
@@ -25,6 +32,7 @@ type versionSpec struct {
 
 func NewNetwork(us stmgr.UpgradeSchedule, current network.Version) *Network {
 	var networkVersions []versionSpec
+	var upgrades []Upgrade
 	lastVersion := network.Version0
 	if len(us) > 0 {
 		for _, upgrade := range us {
@@ -32,6 +40,10 @@ func NewNetwork(us stmgr.UpgradeSchedule, current network.Version) *Network {
 				networkVersion: lastVersion,
 				atOrBelow:      upgrade.Height,
 			})
+			upgrades = append(upgrades, Upgrade{
+				Height:  upgrade.Height,
+				Network: upgrade.Network,
+			})
 			lastVersion = upgrade.Network
 		}
 	} else {
@@ -41,9 +53,15 @@ func NewNetwork(us stmgr.UpgradeSchedule, current network.Version) *Network {
 	return &Network{
 		networkVersions: networkVersions,
 		latestVersion:   lastVersion,
+		upgrades:        upgrades,
 	}
 }
 
+// Upgrades returns the upgrades scheduled for the network in the order they take effect.
+func (n *Network) Upgrades() []Upgrade {
+	return n.upgrades
+}
+
 func (n *Network) Version(ctx context.Context, height abi.ChainEpoch) network.Version {
 	// The epochs here are the _last_ epoch for every version, or -1 if the
 	// version is disabled.
@@ -136,10 +136,12 @@ func main() {
 		HideHelp: true,
 		Metadata: commands.Metadata(),
 		Commands: []*cli.Command{
+			commands.AuthCmd,
 			commands.ChainCmd,
 			commands.DaemonCmd,
 			commands.GapCmd,
 			commands.HelpCmd,
+			commands.IndexCmd,
 			commands.InitCmd,
 			commands.JobCmd,
 			commands.LogCmd,
@@ -147,6 +149,7 @@ func main() {
 			commands.NetCmd,
 			commands.StopCmd,
 			commands.SyncCmd,
+			commands.ViewCmd,
 			commands.WaitApiCmd,
 			commands.WalkCmd,
 			commands.WatchCmd,
@@ -38,6 +38,11 @@ var (
 	JobComplete             = stats.Int64("job_complete", "Number of jobs completed without error", stats.UnitDimensionless)
 	JobError                = stats.Int64("job_error", "Number of jobs stopped due to a fatal error", stats.UnitDimensionless)
 	JobTimeout              = stats.Int64("job_timeout", "Number of jobs stopped due to taking longer than expected", stats.UnitDimensionless)
+	JobHeight               = stats.Int64("job_height", "The height most recently processed by a job", stats.UnitDimensionless)
+	JobHeightsProcessed     = stats.Int64("job_heights_processed", "Number of heights processed by a job since it was last started", stats.UnitDimensionless)
+	JobHeightsRemaining     = stats.Int64("job_heights_remaining", "Number of heights a job has left to process, or the number of heights a watch is behind the chain head", stats.UnitDimensionless)
+	JobTipSetsPerMinute     = stats.Float64("job_tipsets_per_minute", "Average rate at which a job has processed heights since it was last started", stats.UnitDimensionless)
+	JobETA                  = stats.Float64("job_eta_seconds", "Estimated time until a job completes, zero when no estimate can be made", stats.UnitSeconds)
 	TipSetCacheSize         = stats.Int64("tipset_cache_size", "Configured size of the tipset cache (aka confidence).", stats.UnitDimensionless)
 	TipSetCacheDepth        = stats.Int64("tipset_cache_depth", "Number of tipsets currently in the tipset cache.", stats.UnitDimensionless)
 	TipSetCacheEmptyRevert  = stats.Int64("tipset_cache_empty_revert", "Number of revert operations performed on an empty tipset cache. This is an indication that a chain reorg is underway that is deeper than the cache size and includes tipsets that have already been read from the cache.", stats.UnitDimensionless)
@@ -129,6 +134,32 @@ var DefaultViews = []*view.View{
 		TagKeys:     []tag.Key{Job},
 	},
 
+	{
+		Measure:     JobHeight,
+		Aggregation: view.LastValue(),
+		TagKeys:     []tag.Key{Job},
+	},
+	{
+		Measure:     JobHeightsProcessed,
+		Aggregation: view.LastValue(),
+		TagKeys:     []tag.Key{Job},
+	},
+	{
+		Measure:     JobHeightsRemaining,
+		Aggregation: view.LastValue(),
+		TagKeys:     []tag.Key{Job},
+	},
+	{
+		Measure:     JobTipSetsPerMinute,
+		Aggregation: view.LastValue(),
+		TagKeys:     []tag.Key{Job},
+	},
+	{
+		Measure:     JobETA,
+		Aggregation: view.LastValue(),
+		TagKeys:     []tag.Key{Job},
+	},
+
 	{
 		Name:        PersistModel.Name() + "_total",
 		Measure:     PersistModel,
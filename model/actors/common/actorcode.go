@@ -0,0 +1,106 @@
+package common
+
+import (
+	"context"
+
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+)
+
+// ActorCode describes the code of a builtin actor. It is reference data that does not depend on the chain.
+type ActorCode struct {
+	Code    string `pg:",pk,notnull"`
+	Name    string `pg:",notnull"`
+	Family  string `pg:",notnull"`
+	Version int64  `pg:",notnull,use_zero"`
+}
+
+// ActorCodeList is a slice of ActorCodes persistable in a single batch.
+type ActorCodeList []*ActorCode
+
+// MergeConflict replaces the description of any existing code so the table can be refreshed with newer versions of
+// lily.
+func (l ActorCodeList) MergeConflict() (string, string) {
+	return "(code) DO UPDATE", `"name" = EXCLUDED."name", "family" = EXCLUDED."family", "version" = EXCLUDED."version"`
+}
+
+func (l ActorCodeList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "ActorCodeList.Persist", trace.WithAttributes(label.Int("count", len(l))))
+	defer span.End()
+
+	if len(l) == 0 {
+		return nil
+	}
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "actor_codes"))
+	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, l)
+}
+
+// ActorMethod names a method of a builtin actor.
+type ActorMethod struct {
+	Code   string `pg:",pk,notnull"`
+	Method uint64 `pg:",pk,notnull,use_zero"`
+	Name   string `pg:",notnull"`
+}
+
+// ActorMethodList is a slice of ActorMethods persistable in a single batch.
+type ActorMethodList []*ActorMethod
+
+func (l ActorMethodList) MergeConflict() (string, string) {
+	return "(code, method) DO UPDATE", `"name" = EXCLUDED."name"`
+}
+
+func (l ActorMethodList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "ActorMethodList.Persist", trace.WithAttributes(label.Int("count", len(l))))
+	defer span.End()
+
+	if len(l) == 0 {
+		return nil
+	}
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "actor_methods"))
+	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, l)
+}
+
+// ActorExitCode names an exit code that may be returned by a builtin actor.
+type ActorExitCode struct {
+	Code     string `pg:",pk,notnull"`
+	ExitCode int64  `pg:",pk,notnull,use_zero"`
+	Name     string `pg:",notnull"`
+}
+
+// ActorExitCodeList is a slice of ActorExitCodes persistable in a single batch.
+type ActorExitCodeList []*ActorExitCode
+
+func (l ActorExitCodeList) MergeConflict() (string, string) {
+	return "(code, exit_code) DO UPDATE", `"name" = EXCLUDED."name"`
+}
+
+func (l ActorExitCodeList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "ActorExitCodeList.Persist", trace.WithAttributes(label.Int("count", len(l))))
+	defer span.End()
+
+	if len(l) == 0 {
+		return nil
+	}
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "actor_exit_codes"))
+	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, l)
+}
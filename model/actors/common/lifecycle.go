@@ -0,0 +1,64 @@
+package common
+
+import (
+	"context"
+
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+)
+
+const (
+	ActorCreated  = "CREATED"  // actor was added to the state tree
+	ActorDeleted  = "DELETED"  // actor was removed from the state tree
+	ActorUpgraded = "UPGRADED" // code of the actor was changed, which only happens during network upgrades
+)
+
+type ActorLifecycleEvent struct {
+	Height    int64  `pg:",pk,notnull,use_zero"`
+	ID        string `pg:",pk,notnull"`
+	StateRoot string `pg:",pk,notnull"`
+	Event     string `pg:",pk,notnull"`
+	Address   string `pg:",notnull"`
+	Code      string `pg:",notnull"`
+	PrevCode  string
+}
+
+func (e *ActorLifecycleEvent) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	if e == nil {
+		// Nothing to do
+		return nil
+	}
+
+	ctx, span := global.Tracer("").Start(ctx, "ActorLifecycleEvent.Persist")
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "actor_lifecycle_events"))
+	metrics.RecordCount(ctx, metrics.PersistModel, 1)
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, e)
+}
+
+// ActorLifecycleEventList is a slice of ActorLifecycleEvents persistable in a single batch.
+type ActorLifecycleEventList []*ActorLifecycleEvent
+
+func (l ActorLifecycleEventList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "ActorLifecycleEventList.Persist", trace.WithAttributes(label.Int("count", len(l))))
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "actor_lifecycle_events"))
+	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	if len(l) == 0 {
+		return nil
+	}
+	return s.PersistModel(ctx, l)
+}
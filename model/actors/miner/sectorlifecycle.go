@@ -0,0 +1,129 @@
+package miner
+
+import (
+	"context"
+
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+)
+
+// MinerSectorLifecycle summarizes the lifecycle of a single sector. Each instance holds only the epochs of the
+// events observed in one state change and is merged with the row already stored for the sector. Epochs of first
+// occurrences keep the earliest value seen and epochs of repeated events keep the latest, so tipsets may be
+// processed in any order and more than once.
+type MinerSectorLifecycle struct {
+	//lint:ignore U1000 tableName is a convention used by go-pg
+	tableName struct{} `pg:"miner_sector_lifecycles"`
+	MinerID   string   `pg:",pk,notnull"`
+	SectorID  uint64   `pg:",pk,use_zero"`
+
+	PreCommitEpoch     *int64
+	ProveCommitEpoch   *int64
+	FirstFaultEpoch    *int64
+	LastRecoveryEpoch  *int64
+	LastExtensionEpoch *int64
+	TerminationEpoch   *int64
+	ExpirationEpoch    *int64
+
+	// SealingDuration is the number of epochs between precommit and prove-commit. ActiveDuration is the number of
+	// epochs between prove-commit and termination or expiration.
+	SealingDuration *int64
+	ActiveDuration  *int64
+
+	LastEvent       string `pg:"type:miner_sector_event_type,notnull"`
+	LastEventHeight int64  `pg:",notnull,use_zero"`
+}
+
+const minerSectorLifecycleConflict = "(miner_id, sector_id) DO UPDATE"
+
+const minerSectorLifecycleMerge = `pre_commit_epoch = LEAST(?TableAlias.pre_commit_epoch, EXCLUDED.pre_commit_epoch),
+	prove_commit_epoch = LEAST(?TableAlias.prove_commit_epoch, EXCLUDED.prove_commit_epoch),
+	first_fault_epoch = LEAST(?TableAlias.first_fault_epoch, EXCLUDED.first_fault_epoch),
+	last_recovery_epoch = GREATEST(?TableAlias.last_recovery_epoch, EXCLUDED.last_recovery_epoch),
+	last_extension_epoch = GREATEST(?TableAlias.last_extension_epoch, EXCLUDED.last_extension_epoch),
+	termination_epoch = LEAST(?TableAlias.termination_epoch, EXCLUDED.termination_epoch),
+	expiration_epoch = LEAST(?TableAlias.expiration_epoch, EXCLUDED.expiration_epoch),
+	sealing_duration = LEAST(?TableAlias.prove_commit_epoch, EXCLUDED.prove_commit_epoch) - LEAST(?TableAlias.pre_commit_epoch, EXCLUDED.pre_commit_epoch),
+	active_duration = COALESCE(LEAST(?TableAlias.termination_epoch, EXCLUDED.termination_epoch), LEAST(?TableAlias.expiration_epoch, EXCLUDED.expiration_epoch)) - LEAST(?TableAlias.prove_commit_epoch, EXCLUDED.prove_commit_epoch),
+	last_event = CASE WHEN EXCLUDED.last_event_height >= ?TableAlias.last_event_height THEN EXCLUDED.last_event ELSE ?TableAlias.last_event END,
+	last_event_height = GREATEST(?TableAlias.last_event_height, EXCLUDED.last_event_height)`
+
+// Apply records the epoch of a sector event against the lifecycle.
+func (msl *MinerSectorLifecycle) Apply(event string, height int64) {
+	epoch := height
+	switch event {
+	case PreCommitAdded:
+		msl.PreCommitEpoch = &epoch
+	case SectorAdded, CommitCapacityAdded:
+		msl.ProveCommitEpoch = &epoch
+	case SectorFaulted:
+		msl.FirstFaultEpoch = &epoch
+	case SectorRecovered:
+		msl.LastRecoveryEpoch = &epoch
+	case SectorExtended:
+		msl.LastExtensionEpoch = &epoch
+	case SectorTerminated:
+		msl.TerminationEpoch = &epoch
+	case SectorExpired:
+		msl.ExpirationEpoch = &epoch
+	}
+	msl.LastEvent = event
+	msl.LastEventHeight = height
+
+	if msl.PreCommitEpoch != nil && msl.ProveCommitEpoch != nil {
+		d := *msl.ProveCommitEpoch - *msl.PreCommitEpoch
+		msl.SealingDuration = &d
+	}
+	end := msl.TerminationEpoch
+	if end == nil {
+		end = msl.ExpirationEpoch
+	}
+	if end != nil && msl.ProveCommitEpoch != nil {
+		d := *end - *msl.ProveCommitEpoch
+		msl.ActiveDuration = &d
+	}
+}
+
+func (msl *MinerSectorLifecycle) MergeConflict() (string, string) {
+	return minerSectorLifecycleConflict, minerSectorLifecycleMerge
+}
+
+func (msl *MinerSectorLifecycle) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "miner_sector_lifecycles"))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	metrics.RecordCount(ctx, metrics.PersistModel, 1)
+	return s.PersistModel(ctx, msl)
+}
+
+// MinerSectorLifecycleList is a list of lifecycle updates. It must not contain more than one entry for a sector.
+type MinerSectorLifecycleList []*MinerSectorLifecycle
+
+func (l MinerSectorLifecycleList) MergeConflict() (string, string) {
+	return minerSectorLifecycleConflict, minerSectorLifecycleMerge
+}
+
+func (l MinerSectorLifecycleList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "MinerSectorLifecycleList.Persist", trace.WithAttributes(label.Int("count", len(l))))
+	defer span.End()
+
+	if len(l) == 0 {
+		return nil
+	}
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "miner_sector_lifecycles"))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
+	return s.PersistModel(ctx, l)
+}
+
+var _ model.Merger = (*MinerSectorLifecycle)(nil)
+var _ model.Merger = (MinerSectorLifecycleList)(nil)
@@ -0,0 +1,51 @@
+package power
+
+import (
+	"context"
+
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+)
+
+// PowerActorClaimConsensus records whether a miner's claim meets the consensus minimum power requirement at the
+// epoch the claim changed, or at the epoch the consensus minimum may have changed for every claim.
+type PowerActorClaimConsensus struct {
+	Height                int64  `pg:",pk,notnull,use_zero"`
+	MinerID               string `pg:",pk,notnull"`
+	StateRoot             string `pg:",pk,notnull"`
+	MeetsConsensusMinimum bool   `pg:",notnull,use_zero"`
+}
+
+func (p *PowerActorClaimConsensus) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "PowerActorClaimConsensus.Persist")
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "power_actor_claim_consensus"))
+	metrics.RecordCount(ctx, metrics.PersistModel, 1)
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, p)
+}
+
+type PowerActorClaimConsensusList []*PowerActorClaimConsensus
+
+func (pl PowerActorClaimConsensusList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "PowerActorClaimConsensusList.Persist", trace.WithAttributes(label.Int("count", len(pl))))
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "power_actor_claim_consensus"))
+	metrics.RecordCount(ctx, metrics.PersistModel, len(pl))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	if len(pl) == 0 {
+		return nil
+	}
+	return s.PersistModel(ctx, pl)
+}
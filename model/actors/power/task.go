@@ -7,8 +7,9 @@ import (
 )
 
 type PowerTaskResult struct {
-	ChainPowerModel *ChainPower
-	ClaimStateModel PowerActorClaimList
+	ChainPowerModel     *ChainPower
+	ClaimStateModel     PowerActorClaimList
+	ClaimConsensusModel PowerActorClaimConsensusList
 }
 
 func (p *PowerTaskResult) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
@@ -22,5 +23,10 @@ func (p *PowerTaskResult) Persist(ctx context.Context, s model.StorageBatch, ver
 			return err
 		}
 	}
+	if p.ClaimConsensusModel != nil {
+		if err := p.ClaimConsensusModel.Persist(ctx, s, version); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,63 @@
+package chain
+
+import (
+	"context"
+
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+)
+
+// EpochSummary summarizes the blocks of a tipset and the execution of their messages. Epochs without a tipset are
+// summarized as null rounds with no blocks.
+type EpochSummary struct {
+	//lint:ignore U1000 tableName is a convention used by go-pg
+	tableName      struct{} `pg:"epoch_summaries"`
+	Height         int64    `pg:",pk,notnull,use_zero"`
+	StateRoot      string   `pg:",pk,notnull"`
+	NullRound      bool     `pg:",notnull,use_zero"`
+	BlockCount     int64    `pg:",notnull,use_zero"`
+	TotalMessages  int64    `pg:",notnull,use_zero"`
+	UniqueMessages int64    `pg:",notnull,use_zero"`
+	GasUsed        int64    `pg:",notnull,use_zero"`
+	GasLimit       int64    `pg:",notnull,use_zero"`
+	BaseFee        string   `pg:"type:numeric,notnull"`
+	TotalBurnt     string   `pg:"type:numeric,notnull"`
+	TotalMinerTip  string   `pg:"type:numeric,notnull"`
+	MinTimestamp   uint64   `pg:",use_zero"`
+	MaxTimestamp   uint64   `pg:",use_zero"`
+}
+
+func (es *EpochSummary) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "EpochSummary.Persist")
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "epoch_summaries"))
+	metrics.RecordCount(ctx, metrics.PersistModel, 1)
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, es)
+}
+
+type EpochSummaryList []*EpochSummary
+
+func (l EpochSummaryList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "EpochSummaryList.Persist", trace.WithAttributes(label.Int("count", len(l))))
+	defer span.End()
+
+	if len(l) == 0 {
+		return nil
+	}
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "epoch_summaries"))
+	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, l)
+}
@@ -0,0 +1,61 @@
+package chain
+
+import (
+	"context"
+
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+)
+
+// NetworkVersion records the versions of the network, actors and state tree in effect from an epoch onwards. A row is
+// only recorded at epochs where one of the versions changed.
+type NetworkVersion struct {
+	Height           int64  `pg:",pk,notnull,use_zero"`
+	StateRoot        string `pg:",pk,notnull"`
+	NetworkVersion   int64  `pg:",notnull,use_zero"`
+	ActorsVersion    int64  `pg:",notnull,use_zero"`
+	StateTreeVersion int64  `pg:",notnull,use_zero"`
+}
+
+func (nv *NetworkVersion) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "NetworkVersion.Persist")
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "network_versions"))
+	metrics.RecordCount(ctx, metrics.PersistModel, 1)
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, nv)
+}
+
+// NetworkUpgrade is an upgrade scheduled for the network lily is connected to. Several upgrades may share a network
+// version so upgrades are identified by their height as well.
+type NetworkUpgrade struct {
+	Height         int64 `pg:",pk,notnull,use_zero"`
+	NetworkVersion int64 `pg:",pk,notnull,use_zero"`
+	ActorsVersion  int64 `pg:",notnull,use_zero"`
+}
+
+type NetworkUpgradeList []*NetworkUpgrade
+
+func (l NetworkUpgradeList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "NetworkUpgradeList.Persist", trace.WithAttributes(label.Int("count", len(l))))
+	defer span.End()
+
+	if len(l) == 0 {
+		return nil
+	}
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "network_upgrades"))
+	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, l)
+}
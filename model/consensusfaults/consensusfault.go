@@ -0,0 +1,67 @@
+package consensusfaults
+
+import (
+	"context"
+
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+)
+
+const (
+	DoubleForkMining = "DOUBLE_FORK_MINING"
+	TimeOffsetMining = "TIME_OFFSET_MINING"
+	ParentGrinding   = "PARENT_GRINDING"
+)
+
+type ConsensusFault struct {
+	Height    int64  `pg:",pk,notnull,use_zero"`
+	StateRoot string `pg:",pk,notnull"`
+	Message   string `pg:",pk,notnull"` // cid of the ReportConsensusFault message
+
+	Reporter   string `pg:",notnull"`
+	Miner      string `pg:",notnull"`
+	FaultEpoch int64  `pg:",notnull,use_zero"`
+	FaultType  string `pg:",notnull,type:consensus_fault_type"`
+
+	BlockHeader1     string `pg:",notnull"`
+	BlockHeader2     string `pg:",notnull"`
+	BlockHeaderExtra string
+
+	Penalty        string `pg:"type:numeric,notnull"`
+	ReporterReward string `pg:"type:numeric,notnull"`
+}
+
+func (cf *ConsensusFault) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "ConsensusFault.Persist")
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "consensus_faults"))
+	metrics.RecordCount(ctx, metrics.PersistModel, 1)
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, cf)
+}
+
+type ConsensusFaultList []*ConsensusFault
+
+func (cfl ConsensusFaultList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "ConsensusFaultList.Persist", trace.WithAttributes(label.Int("count", len(cfl))))
+	defer span.End()
+
+	if len(cfl) == 0 {
+		return nil
+	}
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "consensus_faults"))
+	metrics.RecordCount(ctx, metrics.PersistModel, len(cfl))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, cfl)
+}
@@ -0,0 +1,58 @@
+package derived
+
+import (
+	"context"
+
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+)
+
+// BlockReward attributes the block reward paid by the reward actor to the block that won it.
+type BlockReward struct {
+	//lint:ignore U1000 tableName is a convention used by go-pg
+	tableName   struct{} `pg:"block_rewards"`
+	Height      int64    `pg:",pk,use_zero,notnull"`
+	Block       string   `pg:",pk,notnull"`
+	StateRoot   string   `pg:",pk,notnull"`
+	Miner       string   `pg:",notnull"`
+	WinCount    int64    `pg:",use_zero,notnull"`
+	GasReward   string   `pg:"type:numeric,notnull"`
+	Penalty     string   `pg:"type:numeric,notnull"`
+	Reward      string   `pg:"type:numeric,notnull"`
+	TotalReward string   `pg:"type:numeric,notnull"`
+}
+
+func (br *BlockReward) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "BlockReward.Persist")
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "block_rewards"))
+	metrics.RecordCount(ctx, metrics.PersistModel, 1)
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, br)
+}
+
+type BlockRewardList []*BlockReward
+
+func (brl BlockRewardList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "BlockRewardList.Persist", trace.WithAttributes(label.Int("count", len(brl))))
+	defer span.End()
+
+	if len(brl) == 0 {
+		return nil
+	}
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "block_rewards"))
+	metrics.RecordCount(ctx, metrics.PersistModel, len(brl))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, brl)
+}
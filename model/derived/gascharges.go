@@ -0,0 +1,63 @@
+package derived
+
+import (
+	"context"
+
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+)
+
+// MessageGasCharge is the sum of the gas charges with the same name made while executing a message. Call is the
+// position of the call that made the charges in a depth first walk of the message's execution trace, where zero is the
+// message itself, or -1 when the charges of all calls made by the message have been summed together.
+type MessageGasCharge struct {
+	//lint:ignore U1000 tableName is a convention used by go-pg
+	tableName         struct{} `pg:"message_gas_charges"`
+	Height            int64    `pg:",pk,use_zero,notnull"`
+	StateRoot         string   `pg:",pk,notnull"`
+	Message           string   `pg:",pk,notnull"`
+	Call              int64    `pg:",pk,use_zero,notnull"`
+	Name              string   `pg:",pk,notnull"`
+	Count             int64    `pg:",use_zero,notnull"`
+	TotalGas          int64    `pg:",use_zero,notnull"`
+	ComputeGas        int64    `pg:",use_zero,notnull"`
+	StorageGas        int64    `pg:",use_zero,notnull"`
+	TotalVirtualGas   int64    `pg:",use_zero,notnull"`
+	VirtualComputeGas int64    `pg:",use_zero,notnull"`
+	VirtualStorageGas int64    `pg:",use_zero,notnull"`
+}
+
+func (mgc *MessageGasCharge) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "MessageGasCharge.Persist")
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "message_gas_charges"))
+	metrics.RecordCount(ctx, metrics.PersistModel, 1)
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, mgc)
+}
+
+type MessageGasChargeList []*MessageGasCharge
+
+func (l MessageGasChargeList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "MessageGasChargeList.Persist", trace.WithAttributes(label.Int("count", len(l))))
+	defer span.End()
+
+	if len(l) == 0 {
+		return nil
+	}
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "message_gas_charges"))
+	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, l)
+}
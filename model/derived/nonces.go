@@ -0,0 +1,95 @@
+package derived
+
+import (
+	"context"
+
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+)
+
+// SenderNonces summarizes the nonces of the messages sent by a single sender that were executed in a tipset. Gap is the
+// difference between the first nonce observed and the nonce of the sender before the tipset was executed, so it is
+// zero unless the sender's nonce could not be determined or messages were executed out of order. Sender is the ID
+// address of the sender so that messages sent from its robust address are part of the same sequence.
+type SenderNonces struct {
+	//lint:ignore U1000 tableName is a convention used by go-pg
+	tableName     struct{} `pg:"message_sender_nonces"`
+	Height        int64    `pg:",pk,use_zero,notnull"`
+	StateRoot     string   `pg:",pk,notnull"`
+	Sender        string   `pg:",pk,notnull"`
+	MessageCount  int64    `pg:",use_zero,notnull"`
+	FirstNonce    uint64   `pg:",use_zero,notnull"`
+	LastNonce     uint64   `pg:",use_zero,notnull"`
+	ExpectedNonce uint64   `pg:",use_zero,notnull"`
+	Gap           int64    `pg:",use_zero,notnull"`
+	Contiguous    bool     `pg:",use_zero,notnull"`
+}
+
+func (sn *SenderNonces) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "SenderNonces.Persist")
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "message_sender_nonces"))
+	metrics.RecordCount(ctx, metrics.PersistModel, 1)
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, sn)
+}
+
+type SenderNoncesList []*SenderNonces
+
+func (l SenderNoncesList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "SenderNoncesList.Persist", trace.WithAttributes(label.Int("count", len(l))))
+	defer span.End()
+
+	if len(l) == 0 {
+		return nil
+	}
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "message_sender_nonces"))
+	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, l)
+}
+
+// NonceReplacement records a message that was executed with the same sender and nonce as a different message that had
+// been executed in a tipset that has since been reverted. Replacements are recorded on a best effort basis: only
+// watches see reverted tipsets and only replacements seen by the same job since it last started are detected.
+type NonceReplacement struct {
+	//lint:ignore U1000 tableName is a convention used by go-pg
+	tableName      struct{} `pg:"message_nonce_replacements"`
+	Height         int64    `pg:",pk,use_zero,notnull"`
+	StateRoot      string   `pg:",pk,notnull"`
+	Sender         string   `pg:",pk,notnull"`
+	Nonce          uint64   `pg:",pk,use_zero,notnull"`
+	Message        string   `pg:",notnull"`
+	ReplacedCid    string   `pg:",notnull"`
+	ReplacedHeight int64    `pg:",use_zero,notnull"`
+	ReplacedTipSet string   `pg:",notnull"`
+}
+
+type NonceReplacementList []*NonceReplacement
+
+func (l NonceReplacementList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, span := global.Tracer("").Start(ctx, "NonceReplacementList.Persist", trace.WithAttributes(label.Int("count", len(l))))
+	defer span.End()
+
+	if len(l) == 0 {
+		return nil
+	}
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "message_nonce_replacements"))
+	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	return s.PersistModel(ctx, l)
+}
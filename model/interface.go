@@ -24,6 +24,14 @@ type StorageBatch interface {
 	PersistModel(ctx context.Context, m interface{}) error
 }
 
+// A Merger is a model that is updated incrementally. Storage that supports updates should merge the model into any
+// existing row with the same primary key instead of ignoring or replacing it. MergeConflict returns the conflict
+// clause and the set expression to use when merging, which may refer to the existing row using ?TableAlias and
+// to the new values using EXCLUDED.
+type Merger interface {
+	MergeConflict() (conflict string, set string)
+}
+
 // A Persistable can persist a full copy of itself or its components as part of a storage batch using a specific
 // version of a schema. Persist should call PersistModel on s with a model containing data that should be persisted.
 // ErrUnsupportedSchemaVersion should be retuned if the Persistable cannot provide a model compatible with the requested
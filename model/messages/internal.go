@@ -27,6 +27,8 @@ type InternalMessage struct {
 	ActorFamily   string `pg:",notnull"`
 	ExitCode      int64  `pg:",use_zero"`
 	GasUsed       int64  `pg:",use_zero"`
+	MethodName    string
+	ExitCodeName  string
 }
 
 func (im *InternalMessage) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
@@ -92,3 +94,40 @@ func (l InternalParsedMessageList) Persist(ctx context.Context, s model.StorageB
 	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
 	return s.PersistModel(ctx, l)
 }
+
+type InternalParsedReceipt struct {
+	//lint:ignore U1000 tableName is a convention used by go-pg
+	tableName struct{} `pg:"internal_parsed_receipts"`
+	Height    int64    `pg:",pk,notnull,use_zero"`
+	Cid       string   `pg:",pk,notnull"`
+	StateRoot string   `pg:",notnull"`
+	ExitCode  int64    `pg:",use_zero"`
+	Method    string   `pg:",use_zero"`
+	Return    string   `pg:",type:jsonb"`
+}
+
+func (ipr *InternalParsedReceipt) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "internal_parsed_receipts"))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	metrics.RecordCount(ctx, metrics.PersistModel, 1)
+	return s.PersistModel(ctx, ipr)
+}
+
+type InternalParsedReceiptList []*InternalParsedReceipt
+
+func (l InternalParsedReceiptList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	if len(l) == 0 {
+		return nil
+	}
+	ctx, span := global.Tracer("").Start(ctx, "InternalParsedReceiptList.Persist", trace.WithAttributes(label.Int("count", len(l))))
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "internal_parsed_receipts"))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
+	return s.PersistModel(ctx, l)
+}
@@ -27,6 +27,8 @@ type Message struct {
 	SizeBytes int    `pg:",use_zero"`
 	Nonce     uint64 `pg:",use_zero"`
 	Method    uint64 `pg:",use_zero"`
+
+	MethodName string // empty when the code of the receiving actor is not known
 }
 
 type MessageV0 struct {
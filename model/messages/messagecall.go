@@ -0,0 +1,61 @@
+package messages
+
+import (
+	"context"
+
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+)
+
+// MessageCall is a single call in the tree of calls made while executing a message. Calls are numbered in the order
+// of a depth first walk of the message's execution trace, so the message itself is call zero and has no parent.
+type MessageCall struct {
+	//lint:ignore U1000 tableName is a convention used by go-pg
+	tableName  struct{} `pg:"message_calls"`
+	Height     int64    `pg:",pk,notnull,use_zero"`
+	StateRoot  string   `pg:",pk,notnull"`
+	Message    string   `pg:",pk,notnull"`
+	Call       int64    `pg:",pk,notnull,use_zero"`
+	Parent     *int64
+	Depth      int64  `pg:",notnull,use_zero"`
+	Sibling    int64  `pg:",notnull,use_zero"`
+	Cid        string `pg:",notnull"`
+	From       string `pg:",notnull"`
+	To         string `pg:",notnull"`
+	Value      string `pg:"type:numeric,notnull"`
+	Method     uint64 `pg:",notnull,use_zero"`
+	ExitCode   int64  `pg:",notnull,use_zero"`
+	GasUsed    int64  `pg:",notnull,use_zero"`
+	ReturnSize int64  `pg:",notnull,use_zero"`
+}
+
+func (mc *MessageCall) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "message_calls"))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	metrics.RecordCount(ctx, metrics.PersistModel, 1)
+	return s.PersistModel(ctx, mc)
+}
+
+type MessageCallList []*MessageCall
+
+func (l MessageCallList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	if len(l) == 0 {
+		return nil
+	}
+	ctx, span := global.Tracer("").Start(ctx, "MessageCallList.Persist", trace.WithAttributes(label.Int("count", len(l))))
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "message_calls"))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
+	return s.PersistModel(ctx, l)
+}
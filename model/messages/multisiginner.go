@@ -0,0 +1,58 @@
+package messages
+
+import (
+	"context"
+
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+)
+
+// MultisigInnerMessage is a call proposed to, approved by or cancelled on a multisig actor, decoded from the message
+// that proposed, approved or cancelled it. Depth is zero for the call carried by the message itself and increases by one for each multisig
+// actor the call passes through on its way to the final recipient.
+type MultisigInnerMessage struct {
+	//lint:ignore U1000 tableName is a convention used by go-pg
+	tableName     struct{} `pg:"multisig_inner_messages"`
+	Height        int64    `pg:",pk,notnull,use_zero"`
+	Cid           string   `pg:",pk,notnull"`
+	Depth         int      `pg:",pk,notnull,use_zero"`
+	StateRoot     string   `pg:",notnull"`
+	MultisigID    string   `pg:",notnull"`
+	TransactionID int64    `pg:",notnull,use_zero"`
+	Applied       bool     `pg:",notnull,use_zero"`
+	To            string   `pg:",notnull"`
+	Value         string   `pg:"type:numeric,notnull"`
+	Method        string   `pg:",notnull"`
+	Params        string   `pg:",type:jsonb"`
+}
+
+func (mim *MultisigInnerMessage) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "multisig_inner_messages"))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	metrics.RecordCount(ctx, metrics.PersistModel, 1)
+	return s.PersistModel(ctx, mim)
+}
+
+type MultisigInnerMessageList []*MultisigInnerMessage
+
+func (l MultisigInnerMessageList) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	if len(l) == 0 {
+		return nil
+	}
+	ctx, span := global.Tracer("").Start(ctx, "MultisigInnerMessageList.Persist", trace.WithAttributes(label.Int("count", len(l))))
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "multisig_inner_messages"))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	metrics.RecordCount(ctx, metrics.PersistModel, len(l))
+	return s.PersistModel(ctx, l)
+}
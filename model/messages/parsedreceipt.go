@@ -0,0 +1,52 @@
+package messages
+
+import (
+	"context"
+
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+)
+
+type ParsedReceipt struct {
+	//lint:ignore U1000 tableName is a convention used by go-pg
+	tableName struct{} `pg:"parsed_receipts"`
+	Height    int64    `pg:",pk,notnull,use_zero"` // note this is the height of the receipt not the message
+	Message   string   `pg:",pk,notnull"`
+	StateRoot string   `pg:",pk,notnull"`
+
+	Idx      int    `pg:",use_zero"`
+	ExitCode int64  `pg:",use_zero"`
+	Method   string `pg:",notnull"`
+	Return   string `pg:",type:jsonb"`
+}
+
+func (pr *ParsedReceipt) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "parsed_receipts"))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	metrics.RecordCount(ctx, metrics.PersistModel, 1)
+	return s.PersistModel(ctx, pr)
+}
+
+type ParsedReceipts []*ParsedReceipt
+
+func (prs ParsedReceipts) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	if len(prs) == 0 {
+		return nil
+	}
+	ctx, span := global.Tracer("").Start(ctx, "ParsedReceipts.Persist", trace.WithAttributes(label.Int("count", len(prs))))
+	defer span.End()
+
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.Table, "parsed_receipts"))
+	stop := metrics.Timer(ctx, metrics.PersistDuration)
+	defer stop()
+
+	metrics.RecordCount(ctx, metrics.PersistModel, len(prs))
+	return s.PersistModel(ctx, prs)
+}
@@ -7,6 +7,7 @@ import (
 	"go.opentelemetry.io/otel/api/global"
 	"go.opentelemetry.io/otel/api/trace"
 	"go.opentelemetry.io/otel/label"
+	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/lily/metrics"
 	"github.com/filecoin-project/lily/model"
@@ -20,6 +21,42 @@ type Receipt struct {
 	Idx      int   `pg:",use_zero"`
 	ExitCode int64 `pg:",use_zero"`
 	GasUsed  int64 `pg:",use_zero"`
+
+	ExitCodeName string // empty when the exit code is not known
+}
+
+type ReceiptV0 struct {
+	//lint:ignore U1000 tableName is a convention used by go-pg
+	tableName struct{} `pg:"receipts"`
+	Height    int64    `pg:",pk,notnull,use_zero"`
+	Message   string   `pg:",pk,notnull"`
+	StateRoot string   `pg:",pk,notnull"`
+
+	Idx      int   `pg:",use_zero"`
+	ExitCode int64 `pg:",use_zero"`
+	GasUsed  int64 `pg:",use_zero"`
+}
+
+func (r *Receipt) AsVersion(version model.Version) (interface{}, bool) {
+	switch version.Major {
+	case 0:
+		if r == nil {
+			return (*ReceiptV0)(nil), true
+		}
+
+		return &ReceiptV0{
+			Height:    r.Height,
+			Message:   r.Message,
+			StateRoot: r.StateRoot,
+			Idx:       r.Idx,
+			ExitCode:  r.ExitCode,
+			GasUsed:   r.GasUsed,
+		}, true
+	case 1:
+		return r, true
+	default:
+		return nil, false
+	}
 }
 
 func (r *Receipt) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
@@ -27,8 +64,13 @@ func (r *Receipt) Persist(ctx context.Context, s model.StorageBatch, version mod
 	stop := metrics.Timer(ctx, metrics.PersistDuration)
 	defer stop()
 
+	vr, ok := r.AsVersion(version)
+	if !ok {
+		return xerrors.Errorf("Receipt not supported for schema version %s", version)
+	}
+
 	metrics.RecordCount(ctx, metrics.PersistModel, 1)
-	return s.PersistModel(ctx, r)
+	return s.PersistModel(ctx, vr)
 }
 
 type Receipts []*Receipt
@@ -44,6 +86,18 @@ func (rs Receipts) Persist(ctx context.Context, s model.StorageBatch, version mo
 	stop := metrics.Timer(ctx, metrics.PersistDuration)
 	defer stop()
 
+	if version.Major != 1 {
+		vrs := make([]interface{}, 0, len(rs))
+		for _, r := range rs {
+			vr, ok := r.AsVersion(version)
+			if !ok {
+				return xerrors.Errorf("Receipt not supported for schema version %s", version)
+			}
+			vrs = append(vrs, vr)
+		}
+		return s.PersistModel(ctx, vrs)
+	}
+
 	metrics.RecordCount(ctx, metrics.PersistModel, len(rs))
 	return s.PersistModel(ctx, rs)
 }
@@ -0,0 +1,70 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronSchedule(t *testing.T) {
+	at := func(s string) time.Time {
+		tm, err := time.Parse("2006-01-02 15:04", s)
+		require.NoError(t, err)
+		return tm
+	}
+
+	testCases := []struct {
+		expr string
+		from string
+		next string
+	}{
+		{expr: "0 2 * * *", from: "2021-06-01 01:59", next: "2021-06-01 02:00"},
+		{expr: "0 2 * * *", from: "2021-06-01 02:00", next: "2021-06-02 02:00"},
+		{expr: "*/15 * * * *", from: "2021-06-01 10:07", next: "2021-06-01 10:15"},
+		{expr: "30 9 * * 1-5", from: "2021-06-04 10:00", next: "2021-06-07 09:30"}, // friday to monday
+		{expr: "0 0 1 * *", from: "2021-12-15 00:00", next: "2022-01-01 00:00"},
+		{expr: "0 0 * * 7", from: "2021-06-01 00:00", next: "2021-06-06 00:00"},  // 7 is sunday
+		{expr: "0 0 13 * 5", from: "2021-06-01 00:00", next: "2021-06-04 00:00"}, // friday or the 13th
+		{expr: "@hourly", from: "2021-06-01 10:07", next: "2021-06-01 11:00"},
+	}
+
+	for _, tc := range testCases {
+		c, err := ParseCron(tc.expr)
+		require.NoError(t, err, tc.expr)
+		assert.Equal(t, at(tc.next), c.Next(at(tc.from)), tc.expr)
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+
+	// impossible dates never fire
+	c, err := ParseCron("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, c.Next(at("2021-06-01 00:00")).IsZero())
+}
+
+func TestParseHeight(t *testing.T) {
+	h, err := ParseHeight("head-2880")
+	require.NoError(t, err)
+	assert.Equal(t, Height{Offset: 2880, Relative: true}, h)
+	assert.EqualValues(t, 7120, h.Resolve(10000))
+	assert.EqualValues(t, 0, h.Resolve(100))
+	assert.Equal(t, "head-2880", h.String())
+
+	h, err = ParseHeight("head")
+	require.NoError(t, err)
+	assert.EqualValues(t, 10000, h.Resolve(10000))
+
+	h, err = ParseHeight("1000")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1000, h.Resolve(10000))
+
+	for _, expr := range []string{"", "head+1", "tail-1", "-5", "head--5"} {
+		_, err := ParseHeight(expr)
+		assert.Error(t, err, expr)
+	}
+}
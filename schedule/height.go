@@ -0,0 +1,61 @@
+package schedule
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Height is a chain height that is either absolute or relative to the head of the chain. Relative heights allow a
+// recurring job to cover a range that moves with the chain, such as the last day of epochs.
+type Height struct {
+	// Offset is the absolute height, or the number of epochs before the head when Relative is true.
+	Offset   int64
+	Relative bool
+}
+
+// ParseHeight parses a height expression. An expression is either a number such as "1000", "head" or "head-N" for
+// the height N epochs before the head of the chain.
+func ParseHeight(expr string) (Height, error) {
+	s := strings.ReplaceAll(expr, " ", "")
+	if s == "head" {
+		return Height{Relative: true}, nil
+	}
+	if strings.HasPrefix(s, "head-") {
+		n, err := strconv.ParseInt(strings.TrimPrefix(s, "head-"), 10, 64)
+		if err != nil || n < 0 {
+			return Height{}, xerrors.Errorf("invalid height expression %q", expr)
+		}
+		return Height{Offset: n, Relative: true}, nil
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return Height{}, xerrors.Errorf("invalid height expression %q", expr)
+	}
+	return Height{Offset: n}, nil
+}
+
+// Resolve returns the absolute height given the height of the head of the chain. Relative heights before genesis
+// resolve to zero.
+func (h Height) Resolve(head int64) int64 {
+	if !h.Relative {
+		return h.Offset
+	}
+	if h.Offset > head {
+		return 0
+	}
+	return head - h.Offset
+}
+
+func (h Height) String() string {
+	switch {
+	case !h.Relative:
+		return strconv.FormatInt(h.Offset, 10)
+	case h.Offset == 0:
+		return "head"
+	default:
+		return "head-" + strconv.FormatInt(h.Offset, 10)
+	}
+}
@@ -0,0 +1,183 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// jobLogSize is the number of log lines kept for each job.
+	jobLogSize = 500
+
+	// jobOutcomeSize is the number of processing outcomes kept for each job.
+	jobOutcomeSize = 50
+)
+
+// LogLine is a line logged by a job.
+type LogLine struct {
+	Time    time.Time
+	Level   string
+	Message string
+	Fields  string // key=value pairs attached to the line
+}
+
+// Outcome is the result of processing a single height by a job.
+type Outcome struct {
+	Height   int64
+	At       time.Time
+	Duration time.Duration
+	Errors   []string // errors encountered while processing the height, empty if processing succeeded
+}
+
+// jobLog keeps the most recent log lines and processing outcomes of a job.
+type jobLog struct {
+	mu       sync.Mutex
+	lines    []LogLine
+	next     int // index of the next line to write once lines is full
+	outcomes []Outcome
+}
+
+func (l *jobLog) addLine(line LogLine) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.lines) < jobLogSize {
+		l.lines = append(l.lines, line)
+		return
+	}
+	l.lines[l.next] = line
+	l.next = (l.next + 1) % jobLogSize
+}
+
+// tail returns up to n of the most recent lines, oldest first. All lines are returned if n is zero or negative.
+func (l *jobLog) tail(n int) []LogLine {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ordered := append(append([]LogLine{}, l.lines[l.next:]...), l.lines[:l.next]...)
+	if n > 0 && n < len(ordered) {
+		ordered = ordered[len(ordered)-n:]
+	}
+	return ordered
+}
+
+func (l *jobLog) addOutcome(o Outcome) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.outcomes = append(l.outcomes, o)
+	if len(l.outcomes) > jobOutcomeSize {
+		l.outcomes = l.outcomes[len(l.outcomes)-jobOutcomeSize:]
+	}
+}
+
+// recentOutcomes returns the retained outcomes, most recent last.
+func (l *jobLog) recentOutcomes() []Outcome {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Outcome{}, l.outcomes...)
+}
+
+// jobLogCore is a zap core that writes info and higher level entries to a job's log, regardless of the level of the
+// daemon's logger.
+type jobLogCore struct {
+	log    *jobLog
+	fields []zapcore.Field
+}
+
+var _ zapcore.Core = (*jobLogCore)(nil)
+
+func (c *jobLogCore) Enabled(lvl zapcore.Level) bool {
+	return lvl >= zapcore.InfoLevel
+}
+
+func (c *jobLogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &jobLogCore{
+		log:    c.log,
+		fields: append(append([]zapcore.Field{}, c.fields...), fields...),
+	}
+}
+
+func (c *jobLogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *jobLogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, enc.Fields[k]))
+	}
+
+	c.log.addLine(LogLine{
+		Time:    ent.Time.UTC(),
+		Level:   ent.Level.String(),
+		Message: ent.Message,
+		Fields:  strings.Join(pairs, " "),
+	})
+	return nil
+}
+
+func (c *jobLogCore) Sync() error {
+	return nil
+}
+
+// newJobLogger returns a logger that writes to the daemon's log and to the job's log.
+func newJobLogger(jc *JobConfig) *zap.SugaredLogger {
+	return withJobLog(&log.SugaredLogger, jc.joblog).With("id", jc.id, "name", jc.Name)
+}
+
+// withJobLog returns a copy of l that also writes to the job's log. The copy keeps the name, level and caller of l
+// so the lines it writes to the daemon's log are unchanged.
+func withJobLog(l *zap.SugaredLogger, jl *jobLog) *zap.SugaredLogger {
+	return l.Desugar().WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(c, &jobLogCore{log: jl})
+	})).Sugar()
+}
+
+type loggerKey struct{}
+
+// jobLoggerCtx identifies the job running with a context for loggers obtained with Logger.
+type jobLoggerCtx struct {
+	id   JobID
+	name string
+	log  *jobLog
+}
+
+type outcomeKey struct{}
+
+// Logger returns def extended to also write to the log of the job running with the context, or def itself for
+// contexts not created by a scheduler. Lines keep the name and level of def in the daemon's log.
+func Logger(ctx context.Context, def *zap.SugaredLogger) *zap.SugaredLogger {
+	if jl, ok := ctx.Value(loggerKey{}).(*jobLoggerCtx); ok {
+		return withJobLog(def, jl.log).With("id", jl.id, "name", jl.name)
+	}
+	return def
+}
+
+// RecordOutcome records the outcome of processing a height in the log of the job running with the context. It does
+// nothing for contexts not created by a scheduler.
+func RecordOutcome(ctx context.Context, o Outcome) {
+	if l, ok := ctx.Value(outcomeKey{}).(*jobLog); ok {
+		l.addOutcome(o)
+	}
+}
@@ -0,0 +1,180 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+)
+
+// Priority orders jobs competing for the scheduler's concurrency slots. Jobs of the highest priority, such as
+// watches, are never limited and their lag throttles jobs of lower priority.
+type Priority int
+
+const (
+	PriorityLow    Priority = iota // jobs such as walks that process history
+	PriorityNormal                 // jobs such as gap fills that repair history
+	PriorityHigh                   // jobs such as watches that follow the chain head
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+// LagReporter is implemented by jobs that follow the head of the chain and can report when they have fallen behind.
+type LagReporter interface {
+	// Lagging returns true if the job has fallen behind the head of the chain.
+	Lagging() bool
+}
+
+// slots limits the number of jobs below PriorityHigh that run at the same time. Waiting jobs are granted slots in
+// order of priority and then in the order they started waiting.
+type slots struct {
+	mu      sync.Mutex
+	limit   int // zero for no limit
+	used    int
+	waiting []*slotWaiter
+}
+
+type slotWaiter struct {
+	priority Priority
+	ready    chan struct{}
+}
+
+// acquire blocks until a slot is available for a job of the given priority or the context is done.
+func (s *slots) acquire(ctx context.Context, p Priority) error {
+	if p >= PriorityHigh {
+		return nil
+	}
+
+	s.mu.Lock()
+	if s.limit == 0 || (s.used < s.limit && len(s.waiting) == 0) {
+		s.used++
+		s.mu.Unlock()
+		return nil
+	}
+
+	w := &slotWaiter{priority: p, ready: make(chan struct{})}
+	idx := len(s.waiting)
+	for i, o := range s.waiting {
+		if o.priority < p {
+			idx = i
+			break
+		}
+	}
+	s.waiting = append(s.waiting, nil)
+	copy(s.waiting[idx+1:], s.waiting[idx:])
+	s.waiting[idx] = w
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for i, o := range s.waiting {
+			if o == w {
+				s.waiting = append(s.waiting[:i], s.waiting[i+1:]...)
+				s.mu.Unlock()
+				return ctx.Err()
+			}
+		}
+		s.mu.Unlock()
+		// the slot was granted while the context was being canceled
+		s.release(p)
+		return ctx.Err()
+	}
+}
+
+// release returns a slot acquired by a job of the given priority and grants it to the next waiting job.
+func (s *slots) release(p Priority) {
+	if p >= PriorityHigh {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used--
+	for len(s.waiting) > 0 && (s.limit == 0 || s.used < s.limit) {
+		w := s.waiting[0]
+		s.waiting = s.waiting[1:]
+		s.used++
+		close(w.ready)
+	}
+}
+
+// throttle pauses jobs below PriorityHigh while a job of PriorityHigh is lagging.
+type throttle struct {
+	mu      sync.Mutex
+	resumed chan struct{} // closed when jobs are not paused
+}
+
+func newThrottle() *throttle {
+	t := &throttle{resumed: make(chan struct{})}
+	close(t.resumed)
+	return t
+}
+
+// set pauses or resumes throttled jobs. It returns true if the state changed.
+func (t *throttle) set(paused bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	select {
+	case <-t.resumed:
+		if paused {
+			t.resumed = make(chan struct{})
+			return true
+		}
+	default:
+		if !paused {
+			close(t.resumed)
+			return true
+		}
+	}
+	return false
+}
+
+func (t *throttle) paused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.resumed:
+		return false
+	default:
+		return true
+	}
+}
+
+func (t *throttle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	resumed := t.resumed
+	t.mu.Unlock()
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type throttleKey struct{}
+
+// WaitIfThrottled blocks while the scheduler has paused the job running with the context, which happens to jobs below
+// PriorityHigh while a job of PriorityHigh is lagging. Jobs that process many tipsets should call it between tipsets.
+// It returns immediately for contexts not created by a scheduler and returns an error if the context is done.
+func WaitIfThrottled(ctx context.Context) error {
+	t, ok := ctx.Value(throttleKey{}).(*throttle)
+	if !ok {
+		return nil
+	}
+	return t.wait(ctx)
+}
@@ -0,0 +1,80 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlots(t *testing.T) {
+	ctx := context.Background()
+	s := &slots{limit: 1}
+
+	// high priority jobs are never limited
+	require.NoError(t, s.acquire(ctx, PriorityHigh))
+	require.NoError(t, s.acquire(ctx, PriorityLow))
+
+	granted := make(chan Priority, 2)
+	go func() {
+		assert.NoError(t, s.acquire(ctx, PriorityLow))
+		granted <- PriorityLow
+	}()
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.waiting) == 1
+	}, time.Second, time.Millisecond)
+
+	go func() {
+		assert.NoError(t, s.acquire(ctx, PriorityNormal))
+		granted <- PriorityNormal
+	}()
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.waiting) == 2
+	}, time.Second, time.Millisecond)
+
+	// the normal priority job overtakes the low priority job that was waiting first
+	s.release(PriorityLow)
+	assert.Equal(t, PriorityNormal, <-granted)
+	s.release(PriorityNormal)
+	assert.Equal(t, PriorityLow, <-granted)
+
+	// waiting can be canceled
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Error(t, s.acquire(cctx, PriorityLow))
+	s.mu.Lock()
+	assert.Len(t, s.waiting, 0)
+	s.mu.Unlock()
+}
+
+func TestThrottle(t *testing.T) {
+	tr := newThrottle()
+	ctx := context.WithValue(context.Background(), throttleKey{}, tr)
+	require.NoError(t, WaitIfThrottled(ctx))
+
+	assert.True(t, tr.set(true))
+	assert.False(t, tr.set(true))
+	assert.True(t, tr.paused())
+
+	resumed := make(chan error)
+	go func() {
+		resumed <- WaitIfThrottled(ctx)
+	}()
+	select {
+	case <-resumed:
+		t.Fatal("throttled job was not paused")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	assert.True(t, tr.set(false))
+	assert.NoError(t, <-resumed)
+
+	// contexts not created by a scheduler are never throttled
+	assert.NoError(t, WaitIfThrottled(context.Background()))
+}
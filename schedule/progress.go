@@ -0,0 +1,105 @@
+package schedule
+
+import (
+	"sync"
+	"time"
+)
+
+// JobProgress is a snapshot of the progress made by a job.
+type JobProgress struct {
+	// CurrentHeight is the height most recently processed by the job.
+	CurrentHeight int64
+
+	// Processed is the number of heights processed since the job was last started.
+	Processed int64
+
+	// Remaining is the number of heights left to process. For jobs that follow the head of the chain it is the
+	// number of heights between the head and CurrentHeight.
+	Remaining int64
+
+	// TipSetsPerMinute is the average rate at which heights have been processed since the job was last started.
+	TipSetsPerMinute float64
+
+	// ETA is the estimated time until the job completes. It is zero for jobs that follow the head of the chain or when
+	// no estimate can be made.
+	ETA time.Duration
+
+	// LastError is the most recent error encountered while processing a height, which may not have stopped the job.
+	LastError   string
+	LastErrorAt time.Time
+}
+
+// ProgressReporter is implemented by jobs that report their progress.
+type ProgressReporter interface {
+	// ReportProgress returns a snapshot of the progress of the job, or nil if the job has not started.
+	ReportProgress() *JobProgress
+}
+
+// ProgressTracker accumulates the progress of a job. The zero value is ready to use and reports no progress until
+// Start is called. It is safe for concurrent use.
+type ProgressTracker struct {
+	mu          sync.Mutex
+	started     time.Time
+	bounded     bool
+	current     int64
+	processed   int64
+	remaining   int64
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// Start resets the tracker when a job starts running. remaining is the number of heights the job will process,
+// bounded is false for jobs that follow the head of the chain and have no end.
+func (t *ProgressTracker) Start(remaining int64, bounded bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.started = time.Now()
+	t.bounded = bounded
+	t.current = 0
+	t.processed = 0
+	t.remaining = remaining
+}
+
+// Processed records that height has been processed and the number of heights that remain.
+func (t *ProgressTracker) Processed(height int64, remaining int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.current = height
+	t.processed++
+	t.remaining = remaining
+}
+
+// Error records an error encountered while processing a height.
+func (t *ProgressTracker) Error(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastError = err.Error()
+	t.lastErrorAt = time.Now().UTC()
+}
+
+// ReportProgress implements ProgressReporter.
+func (t *ProgressTracker) ReportProgress() *JobProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.started.IsZero() {
+		return nil
+	}
+
+	p := &JobProgress{
+		CurrentHeight: t.current,
+		Processed:     t.processed,
+		Remaining:     t.remaining,
+		LastError:     t.lastError,
+		LastErrorAt:   t.lastErrorAt,
+	}
+
+	if elapsed := time.Since(t.started); elapsed > 0 {
+		p.TipSetsPerMinute = float64(t.processed) / elapsed.Minutes()
+	}
+	if t.bounded && p.TipSetsPerMinute > 0 {
+		p.ETA = time.Duration(float64(t.remaining) / p.TipSetsPerMinute * float64(time.Minute))
+	}
+
+	return p
+}
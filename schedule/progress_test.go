@@ -0,0 +1,38 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressTracker(t *testing.T) {
+	var tr ProgressTracker
+	assert.Nil(t, tr.ReportProgress())
+
+	tr.Start(10, true)
+	tr.Processed(100, 9)
+	tr.Processed(99, 8)
+	tr.Error(errors.New("boom"))
+
+	p := tr.ReportProgress()
+	require.NotNil(t, p)
+	assert.EqualValues(t, 99, p.CurrentHeight)
+	assert.EqualValues(t, 2, p.Processed)
+	assert.EqualValues(t, 8, p.Remaining)
+	assert.Greater(t, p.TipSetsPerMinute, float64(0))
+	assert.Greater(t, p.ETA, time.Duration(0))
+	assert.Equal(t, "boom", p.LastError)
+
+	// jobs following the chain head have no ETA
+	tr.Start(0, false)
+	tr.Processed(200, 5)
+	p = tr.ReportProgress()
+	require.NotNil(t, p)
+	assert.EqualValues(t, 1, p.Processed)
+	assert.EqualValues(t, 5, p.Remaining)
+	assert.Equal(t, time.Duration(0), p.ETA)
+}
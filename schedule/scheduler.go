@@ -2,16 +2,22 @@ package schedule
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
 	"github.com/filecoin-project/lotus/node/modules/helpers"
+	"github.com/ipfs/go-datastore"
 	logging "github.com/ipfs/go-log/v2"
+	"go.opencensus.io/stats"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"golang.org/x/xerrors"
 
+	"github.com/filecoin-project/lily/config"
 	"github.com/filecoin-project/lily/metrics"
 	"github.com/filecoin-project/lily/storage"
 	"github.com/filecoin-project/lily/wait"
@@ -41,8 +47,26 @@ type JobConfig struct {
 	// errorMsg will contain a (helpful) string iff a jobs execution has halted due to an error.
 	errorMsg string
 
+	// persisted is true if the job is held in the scheduler's job store.
+	persisted bool
+
+	// nextRunAt is the time a scheduled job will next run, zero if the job is not waiting for its schedule.
+	nextRunAt time.Time
+
+	// queued is true while the job is waiting for a concurrency slot.
+	queued bool
+
+	// completed is true if the most recent execution of the job ran to completion.
+	completed bool
+
+	// ended is closed when the current execution of the job ends.
+	ended chan struct{}
+
 	log *zap.SugaredLogger
 
+	// joblog holds the job's most recent log lines and processing outcomes.
+	joblog *jobLog
+
 	// Name is a human readable name for the job for use in logging
 	Name string
 
@@ -58,7 +82,8 @@ type JobConfig struct {
 	// RestartOnFailure controls whether the job should be restarted if it stops with an error.
 	RestartOnFailure bool
 
-	// RestartOnCompletion controls whether the job should be restarted if it stops without an error.
+	// RestartOnCompletion controls whether the job should be restarted if it stops without an error. It is ignored for
+	// jobs with a Schedule.
 	RestartOnCompletion bool
 
 	// RestartDelay is the amount of time to wait before restarting a stopped job
@@ -75,6 +100,20 @@ type JobConfig struct {
 
 	// EndedAt is the time the job stopped running, either through successful completion or failure. Reset if job is restarted.
 	EndedAt time.Time
+
+	// Config is an optional description of the job, such as the request that created it, from which the job can be
+	// recreated. Jobs with a Config are persisted by a daemon scheduler and resubmitted when the daemon restarts.
+	// Config must be encodable as JSON.
+	Config interface{}
+
+	// Priority orders the job when competing with other jobs for the scheduler's concurrency slots. Jobs below
+	// PriorityHigh may be paused while a job of PriorityHigh is lagging.
+	Priority Priority
+
+	// Schedule is an optional schedule on which the job is run. Instead of starting immediately the job waits for the
+	// schedule to fire and, after each run, waits for it to fire again. Failed runs are restarted according to
+	// RestartOnFailure and RestartDelay.
+	Schedule *CronSchedule
 }
 
 // Locker represents a general lock that a job may need to take before operating.
@@ -101,21 +140,41 @@ func NewScheduler(jobDelay time.Duration, scheduledJobs ...*JobConfig) *Schedule
 		workerJobsRunning: 0,
 
 		daemonMode: false,
+
+		slots:    &slots{},
+		throttle: newThrottle(),
 	}
 
 	// scheduled jobs added here will be started when Scheduler.Run is called.
 	for _, st := range scheduledJobs {
 		s.jobID++
 		st.id = s.jobID
-		st.log = log.With("id", st.id, "name", st.Name)
+		st.joblog = &jobLog{}
+		st.ended = make(chan struct{})
+		st.log = newJobLogger(st)
 		s.jobs[s.jobID] = st
 	}
 	return s
 }
 
-func NewSchedulerDaemon(mctx helpers.MetricsCtx, lc fx.Lifecycle) *Scheduler {
+func NewSchedulerDaemon(mctx helpers.MetricsCtx, lc fx.Lifecycle, ds dtypes.MetadataDS, cfg *config.Conf) (*Scheduler, error) {
 	s := NewScheduler(0)
 	s.daemonMode = true
+	s.store = NewJobStore(ds)
+	s.slots.limit = cfg.Scheduler.MaxConcurrentJobs
+	s.throttleWhenLagging = cfg.Scheduler.ThrottleWhenBehind
+
+	// start numbering jobs after those persisted by a previous run of the daemon so that their IDs remain valid
+	// until they are resubmitted.
+	specs, err := s.store.List()
+	if err != nil {
+		return nil, xerrors.Errorf("list persisted jobs: %w", err)
+	}
+	for _, spec := range specs {
+		if spec.ID > s.jobID {
+			s.jobID = spec.ID
+		}
+	}
 
 	ctx, cancel := context.WithCancel(mctx)
 	go func() {
@@ -132,7 +191,7 @@ func NewSchedulerDaemon(mctx helpers.MetricsCtx, lc fx.Lifecycle) *Scheduler {
 			return nil
 		},
 	})
-	return s
+	return s, nil
 }
 
 type Scheduler struct {
@@ -157,14 +216,43 @@ type Scheduler struct {
 	// if daemonMode is set to true the scheduler will continue to run until its context is canceled.
 	// else the scheduler will exit when all scheduled jobs are complete.
 	daemonMode bool
+
+	// store persists the specs of submitted jobs that have a Config, may be nil.
+	store *JobStore
+
+	// slots limits the number of jobs below PriorityHigh that run concurrently.
+	slots *slots
+
+	// throttle pauses jobs below PriorityHigh while a job of PriorityHigh is lagging, if throttleWhenLagging is set.
+	throttle            *throttle
+	throttleWhenLagging bool
 }
 
+// lagCheckInterval is how often the scheduler checks whether jobs of PriorityHigh are lagging.
+var lagCheckInterval = 5 * time.Second
+
+// progressInterval is how often the progress of a job that reports it is recorded in metrics.
+var progressInterval = 15 * time.Second
+
+// checkpointInterval is how often the progress of a persisted resumable job is written to the job store.
+var checkpointInterval = time.Minute
+
 func (s *Scheduler) Submit(jc *JobConfig) JobID {
 	s.jobIDMu.Lock()
 	defer s.jobIDMu.Unlock()
 
 	s.jobID++
 	jc.id = s.jobID
+	s.persist(jc)
+
+	// register the job before it is queued so that it can be waited on as soon as its ID is returned
+	s.jobsMu.Lock()
+	jc.joblog = &jobLog{}
+	jc.ended = make(chan struct{})
+	jc.log = newJobLogger(jc)
+	s.jobs[jc.id] = jc
+	s.jobsMu.Unlock()
+
 	s.jobQueue <- jc
 
 	return s.jobID
@@ -178,8 +266,30 @@ func (s *Scheduler) Run(ctx context.Context) error {
 	// used as context for jobs submitted, ensure they are canceled when context is canceled.
 	s.context = ctx
 
-	// we don't lock here since jobs can only be written to in the for loop following this.
+	if s.throttleWhenLagging {
+		go func() {
+			ticker := time.NewTicker(lagCheckInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					s.checkLag()
+				}
+			}
+		}()
+	}
+
+	// jobs submitted from now on are registered by Submit and started by the for loop following this.
+	s.jobsMu.Lock()
+	scheduled := make([]*JobConfig, 0, len(s.jobs))
 	for _, tc := range s.jobs {
+		scheduled = append(scheduled, tc)
+	}
+	s.jobsMu.Unlock()
+
+	for _, tc := range scheduled {
 		go s.execute(tc, s.scheduledJobComplete)
 
 		select {
@@ -197,14 +307,8 @@ func (s *Scheduler) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return ctx.Err()
 		case newTask := <-s.jobQueue:
-			s.jobsMu.Lock()
-
-			s.jobs[newTask.id] = newTask
-			newTask.log = log.With("id", newTask.id, "name", newTask.Name)
 			newTask.log.Infow("new job received")
 
-			s.jobsMu.Unlock()
-
 			go s.execute(newTask, s.workerJobComplete)
 		case <-s.scheduledJobComplete:
 			// A job has completed
@@ -225,6 +329,33 @@ func (s *Scheduler) Run(ctx context.Context) error {
 	}
 }
 
+// checkLag pauses jobs below PriorityHigh if any running job of PriorityHigh is lagging and resumes them otherwise.
+func (s *Scheduler) checkLag() {
+	lagging := false
+	s.jobsMu.Lock()
+	for _, j := range s.jobs {
+		if j.Priority < PriorityHigh {
+			continue
+		}
+		j.lk.Lock()
+		running := j.running
+		j.lk.Unlock()
+		if r, ok := j.Job.(LagReporter); ok && running && r.Lagging() {
+			lagging = true
+			break
+		}
+	}
+	s.jobsMu.Unlock()
+
+	if s.throttle.set(lagging) {
+		if lagging {
+			log.Warn("pausing lower priority jobs while a high priority job is lagging")
+		} else {
+			log.Info("resuming lower priority jobs")
+		}
+	}
+}
+
 func (s *Scheduler) StartJob(id JobID) error {
 	s.jobsMu.Lock()
 	defer s.jobsMu.Unlock()
@@ -241,6 +372,7 @@ func (s *Scheduler) StartJob(id JobID) error {
 		job.lk.Unlock()
 		return xerrors.Errorf("starting worker ID: %d already running", id)
 	}
+	job.ended = make(chan struct{})
 	job.lk.Unlock()
 
 	job.log.Info("starting job")
@@ -269,6 +401,194 @@ func (s *Scheduler) StopJob(id JobID) error {
 	return nil
 }
 
+// persist writes the spec of the job to the job store if the scheduler has one and the job has a Config. Failure to
+// persist a job is logged but does not prevent it from running.
+func (s *Scheduler) persist(jc *JobConfig) {
+	if s.store == nil || jc.Config == nil {
+		return
+	}
+
+	cfg, err := json.Marshal(jc.Config)
+	if err != nil {
+		log.Errorw("failed to persist job", "id", jc.id, "name", jc.Name, "error", err)
+		return
+	}
+
+	if err := s.store.Put(&JobSpec{
+		ID:                  jc.id,
+		Name:                jc.Name,
+		Type:                jc.Type,
+		Tasks:               jc.Tasks,
+		Params:              jc.Params,
+		RestartOnFailure:    jc.RestartOnFailure,
+		RestartOnCompletion: jc.RestartOnCompletion,
+		RestartDelay:        jc.RestartDelay,
+		Config:              cfg,
+	}); err != nil {
+		log.Errorw("failed to persist job", "id", jc.id, "name", jc.Name, "error", err)
+		return
+	}
+
+	jc.lk.Lock()
+	jc.persisted = true
+	jc.lk.Unlock()
+}
+
+// checkpoint records the progress of a persisted resumable job in the job store.
+func (s *Scheduler) checkpoint(jc *JobConfig) {
+	r, ok := jc.Job.(Resumable)
+	if !ok {
+		return
+	}
+	progress, ok := r.Progress()
+	if !ok {
+		return
+	}
+
+	spec, err := s.store.Get(jc.id)
+	if err != nil {
+		// the job may have been forgotten
+		if !errors.Is(err, datastore.ErrNotFound) {
+			jc.log.Errorw("failed to read persisted job", "error", err)
+		}
+		return
+	}
+
+	spec.Progress = &progress
+	if err := s.store.Put(spec); err != nil {
+		jc.log.Errorw("failed to record job progress", "error", err)
+	}
+}
+
+// PersistedJobs returns the specs of all jobs held in the job store, including jobs persisted by a previous run of the
+// daemon that have not been resubmitted.
+func (s *Scheduler) PersistedJobs() ([]*JobSpec, error) {
+	if s.store == nil {
+		return nil, nil
+	}
+	return s.store.List()
+}
+
+// ForgetJob removes a job from the job store so that it will not be resubmitted when the daemon restarts. A running
+// job is not stopped.
+func (s *Scheduler) ForgetJob(id JobID) error {
+	if s.store == nil {
+		return xerrors.Errorf("forgetting job ID: %d jobs are not persisted", id)
+	}
+
+	if _, err := s.store.Get(id); err != nil {
+		if errors.Is(err, datastore.ErrNotFound) {
+			return xerrors.Errorf("forgetting job ID: %d not persisted", id)
+		}
+		return xerrors.Errorf("forgetting job ID: %d: %w", id, err)
+	}
+
+	if err := s.store.Delete(id); err != nil {
+		return xerrors.Errorf("forgetting job ID: %d: %w", id, err)
+	}
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	if job, ok := s.jobs[id]; ok {
+		job.lk.Lock()
+		job.persisted = false
+		job.lk.Unlock()
+		job.log.Info("forgot job")
+	}
+	return nil
+}
+
+// RemoveJob removes a stopped job from the scheduler and from the job store so that it will not be resubmitted when
+// the daemon restarts. Running jobs must be stopped before they can be removed.
+func (s *Scheduler) RemoveJob(id JobID) error {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return xerrors.Errorf("removing job ID: %d not found", id)
+	}
+
+	job.lk.Lock()
+	running := job.running
+	persisted := job.persisted
+	job.lk.Unlock()
+	if running {
+		return xerrors.Errorf("removing job ID: %d is running, stop it first", id)
+	}
+
+	if persisted {
+		if err := s.store.Delete(id); err != nil {
+			return xerrors.Errorf("removing job ID: %d: %w", id, err)
+		}
+	}
+
+	delete(s.jobs, id)
+	job.log.Info("removed job")
+	return nil
+}
+
+// WaitJob blocks until the current execution of a job ends, or the context is done, and returns the final state of
+// the job. The job ran to completion if the result is Completed, otherwise it failed with Error or was stopped. Jobs
+// that run on a schedule or restart on completion only end when they are stopped or fail.
+func (s *Scheduler) WaitJob(ctx context.Context, id JobID) (*JobResult, error) {
+	s.jobsMu.Lock()
+	job, ok := s.jobs[id]
+	s.jobsMu.Unlock()
+	if !ok {
+		return nil, xerrors.Errorf("waiting for job ID: %d not found", id)
+	}
+
+	job.lk.Lock()
+	ended := job.ended
+	job.lk.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-ended:
+	}
+
+	res := s.jobResult(job)
+	return &res, nil
+}
+
+// JobDetail is a JobResult with the job's most recent processing outcomes.
+type JobDetail struct {
+	JobResult
+
+	// Outcomes are the most recent outcomes of processing heights by the job, most recent last.
+	Outcomes []Outcome
+}
+
+// Job returns the detail of a job.
+func (s *Scheduler) Job(id JobID) (*JobDetail, error) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, xerrors.Errorf("getting job ID: %d not found", id)
+	}
+	return &JobDetail{
+		JobResult: s.jobResult(job),
+		Outcomes:  job.joblog.recentOutcomes(),
+	}, nil
+}
+
+// JobLogs returns up to n of the most recent lines logged by a job, oldest first. All retained lines are returned if
+// n is zero.
+func (s *Scheduler) JobLogs(id JobID, n int) ([]LogLine, error) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, xerrors.Errorf("getting logs of job ID: %d not found", id)
+	}
+	return job.joblog.tail(n), nil
+}
+
 type JobResult struct {
 	ID    JobID
 	Name  string
@@ -278,6 +598,21 @@ type JobResult struct {
 
 	Running bool
 
+	// Persisted is true if the job will be resubmitted when the daemon restarts.
+	Persisted bool
+
+	// Priority is the priority of the job when competing for concurrency slots.
+	Priority string
+
+	// Queued is true while the job is waiting for a concurrency slot.
+	Queued bool
+
+	// Completed is true if the most recent execution of the job ran to completion without error.
+	Completed bool
+
+	// Throttled is true while the job is paused because a job of higher priority is lagging.
+	Throttled bool
+
 	RestartOnFailure    bool
 	RestartOnCompletion bool
 	RestartDelay        time.Duration
@@ -285,6 +620,15 @@ type JobResult struct {
 	Params    map[string]string
 	StartedAt time.Time
 	EndedAt   time.Time
+
+	// Progress is the progress of jobs that report it, nil otherwise.
+	Progress *JobProgress
+
+	// Schedule is the cron expression on which the job runs, empty if the job is not scheduled.
+	Schedule string
+
+	// NextRunAt is the time a scheduled job will next run, zero while it is running.
+	NextRunAt time.Time
 }
 
 var InvalidJobID = JobID(0)
@@ -300,45 +644,144 @@ func (s *Scheduler) Jobs() []JobResult {
 	}
 	var out []JobResult
 	for _, j := range s.jobs {
-		j.lk.Lock()
-		out = append(out, JobResult{
-			ID:                  j.id,
-			Name:                j.Name,
-			Tasks:               j.Tasks,
-			Type:                j.Type,
-			Error:               j.errorMsg,
-			Running:             j.running,
-			RestartOnFailure:    j.RestartOnFailure,
-			RestartOnCompletion: j.RestartOnCompletion,
-			RestartDelay:        j.RestartDelay,
-			Params:              j.Params,
-			StartedAt:           j.StartedAt,
-			EndedAt:             j.EndedAt,
-		})
-		j.lk.Unlock()
+		out = append(out, s.jobResult(j))
 	}
 	return out
 }
 
+// jobResult describes the current state of a job.
+func (s *Scheduler) jobResult(j *JobConfig) JobResult {
+	j.lk.Lock()
+	defer j.lk.Unlock()
+	res := JobResult{
+		ID:                  j.id,
+		Name:                j.Name,
+		Tasks:               j.Tasks,
+		Type:                j.Type,
+		Error:               j.errorMsg,
+		Running:             j.running,
+		Persisted:           j.persisted,
+		Priority:            j.Priority.String(),
+		Queued:              j.queued,
+		Completed:           j.completed,
+		Throttled:           j.running && j.Priority < PriorityHigh && s.throttle.paused(),
+		RestartOnFailure:    j.RestartOnFailure,
+		RestartOnCompletion: j.RestartOnCompletion,
+		RestartDelay:        j.RestartDelay,
+		Params:              j.Params,
+		StartedAt:           j.StartedAt,
+		EndedAt:             j.EndedAt,
+		Progress:            jobProgress(j),
+		NextRunAt:           j.nextRunAt,
+	}
+	if j.Schedule != nil {
+		res.Schedule = j.Schedule.String()
+	}
+	return res
+}
+
+// jobProgress returns the progress of the job if it reports it.
+func jobProgress(jc *JobConfig) *JobProgress {
+	r, ok := jc.Job.(ProgressReporter)
+	if !ok {
+		return nil
+	}
+	return r.ReportProgress()
+}
+
+// recordProgress records the progress of a job that reports it in metrics.
+func recordProgress(ctx context.Context, jc *JobConfig) {
+	p := jobProgress(jc)
+	if p == nil {
+		return
+	}
+	stats.Record(ctx,
+		metrics.JobHeight.M(p.CurrentHeight),
+		metrics.JobHeightsProcessed.M(p.Processed),
+		metrics.JobHeightsRemaining.M(p.Remaining),
+		metrics.JobTipSetsPerMinute.M(p.TipSetsPerMinute),
+		metrics.JobETA.M(p.ETA.Seconds()),
+	)
+}
+
 func (s *Scheduler) execute(jc *JobConfig, complete chan struct{}) {
 	ctx, cancel := context.WithCancel(s.context)
 	ctx = metrics.WithTagValue(ctx, metrics.Job, jc.Name)
+	ctx = context.WithValue(ctx, loggerKey{}, &jobLoggerCtx{id: jc.id, name: jc.Name, log: jc.joblog})
+	ctx = context.WithValue(ctx, outcomeKey{}, jc.joblog)
+	if jc.Priority < PriorityHigh {
+		ctx = context.WithValue(ctx, throttleKey{}, s.throttle)
+	}
 
 	jc.lk.Lock()
 	jc.cancel = cancel
 	jc.running = true
 	jc.StartedAt = time.Now().UTC()
 	jc.EndedAt = time.Time{}
+	jc.completed = false
+	persisted := jc.persisted
+	ended := jc.ended
 	jc.lk.Unlock()
 
+	// completed is set when the job exits cleanly and will not be restarted
+	completed := false
+
+	if persisted {
+		go func() {
+			ticker := time.NewTicker(checkpointInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					s.checkpoint(jc)
+				}
+			}
+		}()
+	}
+
+	if _, ok := jc.Job.(ProgressReporter); ok {
+		go func() {
+			ticker := time.NewTicker(progressInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					recordProgress(ctx, jc)
+				}
+			}
+		}()
+	}
+
 	// Report job is complete when this goroutine exits
 	defer func() {
+		recordProgress(ctx, jc)
+
+		if persisted {
+			if completed {
+				// nothing left to resume
+				if err := s.store.Delete(jc.id); err != nil {
+					jc.log.Errorw("failed to remove completed job from job store", "error", err)
+				}
+				jc.lk.Lock()
+				jc.persisted = false
+				jc.lk.Unlock()
+			} else {
+				s.checkpoint(jc)
+			}
+		}
+
 		complete <- struct{}{}
 
 		jc.lk.Lock()
 		jc.running = false
+		jc.completed = completed
 		jc.EndedAt = time.Now().UTC()
 		jc.cancel()
+		close(ended)
 		jc.lk.Unlock()
 
 		jc.log.Info("job execution ended")
@@ -347,7 +790,7 @@ func (s *Scheduler) execute(jc *JobConfig, complete chan struct{}) {
 	// Attempt to get the job lock if specified
 	if jc.Locker != nil {
 		if err := jc.Locker.Lock(ctx); err != nil {
-			jc.errorMsg = err.Error()
+			jc.setErrorMsg(err.Error())
 			if errors.Is(err, storage.ErrLockNotAcquired) {
 				jc.log.Infow("job not started: lock not acquired")
 				return
@@ -358,21 +801,75 @@ func (s *Scheduler) execute(jc *JobConfig, complete chan struct{}) {
 		defer func() {
 			if err := jc.Locker.Unlock(ctx); err != nil {
 				if !errors.Is(err, context.Canceled) {
-					jc.errorMsg = err.Error()
+					jc.setErrorMsg(err.Error())
 					jc.log.Errorw("failed to unlock job", "error", err.Error())
 				}
 			}
 		}()
 	}
 
-	// Keep this job running forever
+	// Jobs with a schedule are run each time it fires, others are run once
+	for {
+		if jc.Schedule != nil && !s.waitForSchedule(ctx, jc) {
+			return
+		}
+
+		done := s.run(ctx, jc)
+		if jc.Schedule == nil {
+			completed = done
+			return
+		}
+	}
+}
+
+// setErrorMsg records the error that halted the job's execution, or clears it when msg is empty.
+func (jc *JobConfig) setErrorMsg(msg string) {
+	jc.lk.Lock()
+	jc.errorMsg = msg
+	jc.lk.Unlock()
+}
+
+// waitForSchedule blocks until the next time the job's schedule fires. It returns false if the context is done or the
+// schedule will never fire.
+func (s *Scheduler) waitForSchedule(ctx context.Context, jc *JobConfig) bool {
+	next := jc.Schedule.Next(time.Now())
+	if next.IsZero() {
+		msg := fmt.Sprintf("schedule %q has no future runs", jc.Schedule)
+		jc.setErrorMsg(msg)
+		jc.log.Errorw("job not scheduled", "error", msg)
+		return false
+	}
+
+	jc.lk.Lock()
+	jc.nextRunAt = next
+	jc.lk.Unlock()
+	defer func() {
+		jc.lk.Lock()
+		jc.nextRunAt = time.Time{}
+		jc.lk.Unlock()
+	}()
+
+	jc.log.Infow("waiting for next scheduled run", "at", next)
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// run runs the job, restarting it according to its restart policy. It returns true if the job exited cleanly and will
+// not be restarted.
+func (s *Scheduler) run(ctx context.Context, jc *JobConfig) bool {
 	delayNextRestart := false
 	for {
 
 		// Is the context done?
 		select {
 		case <-ctx.Done():
-			return
+			return false
 		default:
 		}
 
@@ -386,11 +883,26 @@ func (s *Scheduler) execute(jc *JobConfig, complete chan struct{}) {
 			delayNextRestart = true
 		}
 
+		jc.lk.Lock()
+		jc.queued = true
+		jc.lk.Unlock()
+		err := s.slots.acquire(ctx, jc.Priority)
+		jc.lk.Lock()
+		jc.queued = false
+		jc.lk.Unlock()
+		if err != nil {
+			return false
+		}
+
+		// an error from a previous attempt no longer applies once the job is retried
+		jc.setErrorMsg("")
+
 		metrics.RecordInc(ctx, metrics.JobStart)
-		err := jc.Job.Run(ctx)
+		err = jc.Job.Run(ctx)
+		s.slots.release(jc.Priority)
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
-				break
+				return false
 			}
 			if errors.Is(err, context.DeadlineExceeded) {
 				metrics.RecordInc(ctx, metrics.JobTimeout)
@@ -399,19 +911,20 @@ func (s *Scheduler) execute(jc *JobConfig, complete chan struct{}) {
 				metrics.RecordInc(ctx, metrics.JobError)
 			}
 			jc.log.Errorw("job exited with failure", "error", err.Error())
-			jc.errorMsg = err.Error()
+			jc.setErrorMsg(err.Error())
 
 			if !jc.RestartOnFailure {
 				// Exit the job
-				break
+				return false
 			}
 		} else {
 			metrics.RecordInc(ctx, metrics.JobComplete)
 			jc.log.Info("job exited cleanly")
 
-			if !jc.RestartOnCompletion {
+			// scheduled jobs are run again when their schedule next fires
+			if !jc.RestartOnCompletion || jc.Schedule != nil {
 				// Exit the job
-				break
+				return true
 			}
 		}
 	}
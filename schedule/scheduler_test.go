@@ -4,10 +4,16 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
 
+	"github.com/filecoin-project/lily/config"
 	"github.com/filecoin-project/lily/schedule"
 )
 
@@ -43,6 +49,12 @@ func (r *testJob) Run(ctx context.Context) error {
 	}
 }
 
+func newTestDaemon(ctx context.Context, t *testing.T, ds datastore.Batching) *schedule.Scheduler {
+	s, err := schedule.NewSchedulerDaemon(ctx, fxtest.NewLifecycle(t), ds, config.DefaultConf())
+	require.NoError(t, err)
+	return s
+}
+
 func TestScheduler(t *testing.T) {
 	t.Run("Scheduler List Jobs", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
@@ -75,7 +87,7 @@ func TestScheduler(t *testing.T) {
 	t.Run("Scheduler Daemon Submit and List Jobs", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
-		s := schedule.NewSchedulerDaemon(ctx, fxtest.NewLifecycle(t))
+		s := newTestDaemon(ctx, t, dssync.MutexWrap(datastore.NewMapDatastore()))
 
 		// should be no jobs on start
 		jobs := s.Jobs()
@@ -103,7 +115,7 @@ func TestScheduler(t *testing.T) {
 	t.Run("Scheduler Daemon start and stop job", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
-		s := schedule.NewSchedulerDaemon(ctx, fxtest.NewLifecycle(t))
+		s := newTestDaemon(ctx, t, dssync.MutexWrap(datastore.NewMapDatastore()))
 
 		// Stopping a job that Dne should fail with error
 		assert.Error(t, s.StopJob(schedule.InvalidJobID))
@@ -156,7 +168,7 @@ func TestScheduler(t *testing.T) {
 	t.Run("Job restarts on failure", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
-		s := schedule.NewSchedulerDaemon(ctx, fxtest.NewLifecycle(t))
+		s := newTestDaemon(ctx, t, dssync.MutexWrap(datastore.NewMapDatastore()))
 
 		tJob := newTestJob()
 		_ = s.Submit(&schedule.JobConfig{
@@ -180,4 +192,205 @@ func TestScheduler(t *testing.T) {
 		jobs = s.Jobs()
 		assert.True(t, jobs[0].Running)
 	})
+
+	t.Run("Wait for job", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s := newTestDaemon(ctx, t, dssync.MutexWrap(datastore.NewMapDatastore()))
+
+		_, err := s.WaitJob(ctx, schedule.InvalidJobID)
+		assert.Error(t, err)
+
+		wait := func(id schedule.JobID) <-chan *schedule.JobResult {
+			out := make(chan *schedule.JobResult, 1)
+			go func() {
+				res, err := s.WaitJob(ctx, id)
+				assert.NoError(t, err)
+				out <- res
+			}()
+			return out
+		}
+
+		// a job that completes
+		tJob := newTestJob()
+		jobID := s.Submit(&schedule.JobConfig{Name: "completes", Job: tJob})
+		<-tJob.started
+		done := wait(jobID)
+		tJob.errChan <- nil
+		<-tJob.stopped
+		res := <-done
+		assert.False(t, res.Running)
+		assert.True(t, res.Completed)
+		assert.Empty(t, res.Error)
+
+		// waiting on an ended job returns immediately
+		res = <-wait(jobID)
+		assert.True(t, res.Completed)
+
+		// a job that fails
+		tJob = newTestJob()
+		jobID = s.Submit(&schedule.JobConfig{Name: "fails", Job: tJob})
+		<-tJob.started
+		done = wait(jobID)
+		tJob.errChan <- errors.New("FAIL")
+		<-tJob.stopped
+		res = <-done
+		assert.False(t, res.Completed)
+		assert.Equal(t, "FAIL", res.Error)
+
+		// a job that is stopped
+		tJob = newTestJob()
+		jobID = s.Submit(&schedule.JobConfig{Name: "stopped", Job: tJob})
+		<-tJob.started
+		done = wait(jobID)
+		require.NoError(t, s.StopJob(jobID))
+		<-tJob.stopped
+		res = <-done
+		assert.False(t, res.Completed)
+		assert.Empty(t, res.Error)
+
+		// a job can be waited on as soon as it is submitted, before it starts
+		tJob = newTestJob()
+		jobID = s.Submit(&schedule.JobConfig{Name: "immediate", Job: tJob})
+		done = wait(jobID)
+		<-tJob.started
+		tJob.errChan <- nil
+		<-tJob.stopped
+		res = <-done
+		assert.True(t, res.Completed)
+
+		// waiting is abandoned when the context is done
+		tJob = newTestJob()
+		jobID = s.Submit(&schedule.JobConfig{Name: "abandoned", Job: tJob})
+		<-tJob.started
+		wctx, wcancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer wcancel()
+		_, err = s.WaitJob(wctx, jobID)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestSchedulerPersistence(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	s := newTestDaemon(ctx, t, ds)
+
+	// jobs without a config are not persisted
+	tJob := newTestJob()
+	s.Submit(&schedule.JobConfig{
+		Name: "transient",
+		Job:  tJob,
+	})
+	<-tJob.started
+
+	pJob := newTestJob()
+	id := s.Submit(&schedule.JobConfig{
+		Name:   "persisted",
+		Type:   "walk",
+		Job:    pJob,
+		Config: map[string]int{"To": 10},
+	})
+	<-pJob.started
+
+	specs, err := s.PersistedJobs()
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	assert.Equal(t, id, specs[0].ID)
+	assert.Equal(t, "walk", specs[0].Type)
+	assert.JSONEq(t, `{"To":10}`, string(specs[0].Config))
+
+	// a new scheduler using the same datastore numbers its jobs after the persisted ones
+	s2 := newTestDaemon(ctx, t, ds)
+	nJob := newTestJob()
+	assert.Equal(t, id+1, s2.Submit(&schedule.JobConfig{Name: "next", Job: nJob}))
+	<-nJob.started
+
+	require.NoError(t, s.ForgetJob(id))
+	specs, err = s.PersistedJobs()
+	require.NoError(t, err)
+	assert.Len(t, specs, 0)
+	assert.Error(t, s.ForgetJob(id))
+
+	// jobs that complete are forgotten
+	cJob := newTestJob()
+	s.Submit(&schedule.JobConfig{
+		Name:   "completes",
+		Job:    cJob,
+		Config: map[string]int{},
+	})
+	<-cJob.started
+	specs, err = s.PersistedJobs()
+	require.NoError(t, err)
+	assert.Len(t, specs, 1)
+
+	cJob.errChan <- nil
+	<-cJob.stopped
+	assert.Eventually(t, func() bool {
+		specs, err := s.PersistedJobs()
+		return err == nil && len(specs) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+// outcomeJob logs a line and records an outcome for each height it processes.
+type outcomeJob struct {
+	heights int
+}
+
+func (j *outcomeJob) Run(ctx context.Context) error {
+	for h := 0; h < j.heights; h++ {
+		var errs []string
+		if h%2 == 1 {
+			errs = append(errs, "odd height")
+		}
+		schedule.Logger(ctx, zap.NewNop().Sugar()).Infow("processed height", "height", h)
+		schedule.RecordOutcome(ctx, schedule.Outcome{Height: int64(h), At: time.Now(), Errors: errs})
+	}
+	return nil
+}
+
+func TestSchedulerJobDetail(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := schedule.NewScheduler(0, &schedule.JobConfig{
+		Name: t.Name(),
+		Job:  &outcomeJob{heights: 100},
+	})
+	require.NoError(t, s.Run(ctx))
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 1)
+	id := jobs[0].ID
+
+	detail, err := s.Job(id)
+	require.NoError(t, err)
+	assert.Equal(t, t.Name(), detail.Name)
+	require.Len(t, detail.Outcomes, 50)
+	assert.EqualValues(t, 50, detail.Outcomes[0].Height)
+	assert.EqualValues(t, 99, detail.Outcomes[49].Height)
+	assert.Equal(t, []string{"odd height"}, detail.Outcomes[49].Errors)
+
+	lines, err := s.JobLogs(id, 0)
+	require.NoError(t, err)
+	var processed []string
+	for _, l := range lines {
+		if l.Message == "processed height" {
+			processed = append(processed, l.Fields)
+		}
+	}
+	require.Len(t, processed, 100)
+	assert.Contains(t, processed[99], "height=99")
+	assert.Contains(t, processed[99], "name="+t.Name())
+
+	lines, err = s.JobLogs(id, 2)
+	require.NoError(t, err)
+	assert.Len(t, lines, 2)
+
+	require.NoError(t, s.RemoveJob(id))
+	assert.Len(t, s.Jobs(), 0)
+	_, err = s.Job(id)
+	assert.Error(t, err)
+	assert.Error(t, s.RemoveJob(id))
 }
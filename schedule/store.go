@@ -0,0 +1,113 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/query"
+	"golang.org/x/xerrors"
+)
+
+// JobSpec is the persisted description of a job from which it can be recreated when the daemon restarts.
+type JobSpec struct {
+	ID     JobID
+	Name   string
+	Type   string
+	Tasks  []string
+	Params map[string]string
+
+	RestartOnFailure    bool
+	RestartOnCompletion bool
+	RestartDelay        time.Duration
+
+	// Config is the JSON encoded configuration used to create the job.
+	Config json.RawMessage
+
+	// Progress is the last progress reported by a resumable job, nil if the job has not reported any progress.
+	Progress *int64
+}
+
+// Resumable is implemented by jobs that can report how far they have progressed so that they can be resumed from
+// that point rather than starting over.
+type Resumable interface {
+	// Progress returns a job specific marker of progress and true, or false if the job has not made any progress.
+	Progress() (int64, bool)
+}
+
+var jobsNamespace = datastore.NewKey("/lily/jobs")
+
+// JobStore persists job specs in a datastore, usually the metadata datastore of the daemon's repo.
+type JobStore struct {
+	ds datastore.Datastore
+}
+
+func NewJobStore(ds datastore.Batching) *JobStore {
+	return &JobStore{
+		ds: namespace.Wrap(ds, jobsNamespace),
+	}
+}
+
+func jobKey(id JobID) datastore.Key {
+	return datastore.NewKey(fmt.Sprintf("%d", id))
+}
+
+// Put writes the spec, replacing any spec with the same ID.
+func (s *JobStore) Put(spec *JobSpec) error {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return xerrors.Errorf("marshal job %d: %w", spec.ID, err)
+	}
+	if err := s.ds.Put(jobKey(spec.ID), b); err != nil {
+		return xerrors.Errorf("put job %d: %w", spec.ID, err)
+	}
+	return nil
+}
+
+// Get returns the spec of the job with the given ID, or datastore.ErrNotFound if it is not persisted.
+func (s *JobStore) Get(id JobID) (*JobSpec, error) {
+	b, err := s.ds.Get(jobKey(id))
+	if err != nil {
+		return nil, err
+	}
+	spec := new(JobSpec)
+	if err := json.Unmarshal(b, spec); err != nil {
+		return nil, xerrors.Errorf("unmarshal job %d: %w", id, err)
+	}
+	return spec, nil
+}
+
+// Delete removes the spec of the job with the given ID. It is not an error to delete a job that is not persisted.
+func (s *JobStore) Delete(id JobID) error {
+	if err := s.ds.Delete(jobKey(id)); err != nil && err != datastore.ErrNotFound {
+		return xerrors.Errorf("delete job %d: %w", id, err)
+	}
+	return nil
+}
+
+// List returns all persisted specs ordered by ID.
+func (s *JobStore) List() ([]*JobSpec, error) {
+	res, err := s.ds.Query(query.Query{})
+	if err != nil {
+		return nil, xerrors.Errorf("query jobs: %w", err)
+	}
+	defer res.Close() // nolint: errcheck
+
+	var specs []*JobSpec
+	for r := range res.Next() {
+		if r.Error != nil {
+			return nil, xerrors.Errorf("query jobs: %w", r.Error)
+		}
+		spec := new(JobSpec)
+		if err := json.Unmarshal(r.Value, spec); err != nil {
+			return nil, xerrors.Errorf("unmarshal job %s: %w", r.Key, err)
+		}
+		specs = append(specs, spec)
+	}
+
+	sort.Slice(specs, func(i, j int) bool { return specs[i].ID < specs[j].ID })
+	return specs, nil
+}
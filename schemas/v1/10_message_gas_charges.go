@@ -0,0 +1,46 @@
+package v1
+
+// Schema version 1 adds gas charges recorded from message execution traces
+
+func init() {
+	patches.Register(
+		10,
+		`
+	-- ----------------------------------------------------------------
+	-- Name: message_gas_charges
+	-- Model: derived.MessageGasCharge
+	-- Growth: About 10 rows per executed message, more when recording each call
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.message_gas_charges (
+		"height"				bigint NOT NULL,
+		"state_root"			text   NOT NULL,
+		"message"				text   NOT NULL,
+		"call"					bigint NOT NULL,
+		"name"					text   NOT NULL,
+		"count"					bigint NOT NULL,
+		"total_gas"				bigint NOT NULL,
+		"compute_gas"			bigint NOT NULL,
+		"storage_gas"			bigint NOT NULL,
+		"total_virtual_gas"		bigint NOT NULL,
+		"virtual_compute_gas"	bigint NOT NULL,
+		"virtual_storage_gas"	bigint NOT NULL,
+
+		PRIMARY KEY ("height", "state_root", "message", "call", "name")
+	);
+	CREATE INDEX IF NOT EXISTS message_gas_charges_name_idx ON {{ .SchemaName | default "public"}}.message_gas_charges USING hash (name);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.message_gas_charges IS 'Gas charged while executing messages, summed by the name of the charge.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_gas_charges.height IS 'Epoch this message was executed at.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_gas_charges.state_root IS 'CID of the parent state root at which this message was executed.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_gas_charges.message IS 'CID of the message.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_gas_charges.call IS 'Position of the call that was charged in a depth first walk of the execution trace of the message, where 0 is the message itself. -1 when the charges of all calls are summed together.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_gas_charges.name IS 'Name of the gas charge, such as OnIpldGet or OnVerifyPost.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_gas_charges.count IS 'Number of charges with this name.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_gas_charges.total_gas IS 'Sum of the total gas of the charges.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_gas_charges.compute_gas IS 'Sum of the compute gas of the charges.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_gas_charges.storage_gas IS 'Sum of the storage gas of the charges.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_gas_charges.total_virtual_gas IS 'Sum of the total virtual gas of the charges. Virtual gas is accounted but not charged.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_gas_charges.virtual_compute_gas IS 'Sum of the virtual compute gas of the charges.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_gas_charges.virtual_storage_gas IS 'Sum of the virtual storage gas of the charges.';
+`)
+}
@@ -0,0 +1,52 @@
+package v1
+
+// Schema version 1 adds the tree of calls made by executed messages
+
+func init() {
+	patches.Register(
+		11,
+		`
+	-- ----------------------------------------------------------------
+	-- Name: message_calls
+	-- Model: messages.MessageCall
+	-- Growth: About 3 rows per executed message
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.message_calls (
+		"height"		bigint  NOT NULL,
+		"state_root"	text    NOT NULL,
+		"message"		text    NOT NULL,
+		"call"			bigint  NOT NULL,
+		"parent"		bigint,
+		"depth"			bigint  NOT NULL,
+		"sibling"		bigint  NOT NULL,
+		"cid"			text    NOT NULL,
+		"from"			text    NOT NULL,
+		"to"			text    NOT NULL,
+		"value"			numeric NOT NULL,
+		"method"		bigint  NOT NULL,
+		"exit_code"		bigint  NOT NULL,
+		"gas_used"		bigint  NOT NULL,
+		"return_size"	bigint  NOT NULL,
+
+		PRIMARY KEY ("height", "state_root", "message", "call")
+	);
+	CREATE INDEX IF NOT EXISTS message_calls_to_idx ON {{ .SchemaName | default "public"}}.message_calls USING hash ("to");
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.message_calls IS 'Calls made while executing messages, forming a tree for each message.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls.height IS 'Epoch this message was executed at.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls.state_root IS 'CID of the parent state root at which this message was executed.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls.message IS 'CID of the message that was executed.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls.call IS 'Position of the call in a depth first walk of the execution trace of the message, where 0 is the message itself.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls.parent IS 'Position of the call that made this call. Null for the message itself.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls.depth IS 'Number of calls between this call and the message, where 0 is the message itself.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls.sibling IS 'Order of the call among the calls made by its parent.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls.cid IS 'CID of the call.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls."from" IS 'Address of the actor that made the call.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls."to" IS 'Address of the actor that received the call.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls.value IS 'Amount of FIL (in attoFIL) transferred by the call.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls.method IS 'The method number invoked on the recipient actor. A method number of 0 is a plain token transfer.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls.exit_code IS 'The exit code that was returned as a result of the call.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls.gas_used IS 'Gas used by the call as recorded in its receipt.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_calls.return_size IS 'Size in bytes of the value returned by the call.';
+`)
+}
@@ -0,0 +1,47 @@
+package v1
+
+// Schema version 1 adds a summary of each epoch
+
+func init() {
+	patches.Register(
+		12,
+		`
+	-- ----------------------------------------------------------------
+	-- Name: epoch_summaries
+	-- Model: chain.EpochSummary
+	-- Growth: One row per epoch
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.epoch_summaries (
+		"height"			bigint  NOT NULL,
+		"state_root"		text    NOT NULL,
+		"null_round"		boolean NOT NULL,
+		"block_count"		bigint  NOT NULL,
+		"total_messages"	bigint  NOT NULL,
+		"unique_messages"	bigint  NOT NULL,
+		"gas_used"			bigint  NOT NULL,
+		"gas_limit"			bigint  NOT NULL,
+		"base_fee"			numeric NOT NULL,
+		"total_burnt"		numeric NOT NULL,
+		"total_miner_tip"	numeric NOT NULL,
+		"min_timestamp"		bigint  NOT NULL,
+		"max_timestamp"		bigint  NOT NULL,
+
+		PRIMARY KEY ("height", "state_root")
+	);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.epoch_summaries IS 'Summary of the blocks of each epoch and the execution of their messages.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.epoch_summaries.height IS 'Epoch this summary is for.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.epoch_summaries.state_root IS 'CID of the parent state root of the tipset at this epoch, or of the last tipset before a null round.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.epoch_summaries.null_round IS 'True when no tipset was produced at this epoch.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.epoch_summaries.block_count IS 'Number of blocks in the tipset.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.epoch_summaries.total_messages IS 'Number of messages included in the blocks of the tipset, counting messages included in more than one block each time.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.epoch_summaries.unique_messages IS 'Number of distinct messages included in the blocks of the tipset.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.epoch_summaries.gas_used IS 'Total gas used by the executed messages of the tipset.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.epoch_summaries.gas_limit IS 'Total gas limit of the distinct messages of the tipset.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.epoch_summaries.base_fee IS 'Base fee in attoFIL per unit gas applied to the messages of the tipset.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.epoch_summaries.total_burnt IS 'Total attoFIL burnt by the executed messages of the tipset, including base fee burn and over estimation burn.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.epoch_summaries.total_miner_tip IS 'Total attoFIL paid to miners as tips by the executed messages of the tipset.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.epoch_summaries.min_timestamp IS 'Earliest timestamp of the blocks in the tipset, in seconds since the Unix epoch. Zero for null rounds.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.epoch_summaries.max_timestamp IS 'Latest timestamp of the blocks in the tipset, in seconds since the Unix epoch. Zero for null rounds.';
+`)
+}
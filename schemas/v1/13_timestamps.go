@@ -0,0 +1,37 @@
+package v1
+
+// Schema version 1 adds a time column to every table with a height column
+
+func init() {
+	patches.Register(
+		13,
+		`
+	-- ----------------------------------------------------------------
+	-- Adds a nullable "time" column holding the wallclock time of the epoch of each row to every table in the schema
+	-- that has a height column. The column is only filled in by storages with EnrichTimestamps enabled. Adding a
+	-- nullable column without a default does not rewrite existing rows. Indexes for time range queries are not
+	-- created here, they are built on request with 'lily migrate --timestamp-indexes'.
+	--
+	-- Notes: partitioning hypertables on time is out of scope for this migration. TimescaleDB cannot change the
+	-- partitioning of a hypertable that already holds data, so hypertables remain partitioned on height and time
+	-- range queries are served by the optional indexes.
+	-- ----------------------------------------------------------------
+	DO $$
+	DECLARE
+		tbl text;
+	BEGIN
+		FOR tbl IN
+			SELECT c.table_name
+			FROM information_schema.columns c
+			JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+			WHERE c.table_schema = '{{ .SchemaName | default "public"}}'
+				AND c.column_name = 'height'
+				AND t.table_type = 'BASE TABLE'
+		LOOP
+			EXECUTE format('ALTER TABLE %I.%I ADD COLUMN IF NOT EXISTS "time" timestamptz', '{{ .SchemaName | default "public"}}', tbl);
+			EXECUTE format('COMMENT ON COLUMN %I.%I."time" IS %L', '{{ .SchemaName | default "public"}}', tbl, 'Wallclock time of the epoch of this row, derived from the minimum timestamp of the blocks in the tipset at that height. Null unless timestamp enrichment was enabled when the row was persisted.');
+		END LOOP;
+	END
+	$$;
+`)
+}
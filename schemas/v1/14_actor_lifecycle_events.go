@@ -0,0 +1,38 @@
+package v1
+
+// Schema version 1 adds actor lifecycle events
+
+func init() {
+	patches.Register(
+		14,
+		`
+	-- ----------------------------------------------------------------
+	-- Name: actor_lifecycle_events
+	-- Model: common.ActorLifecycleEvent
+	-- Growth: One row for each actor created or deleted, plus one row per actor at network upgrades that change actor code
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.actor_lifecycle_events (
+		"height"		bigint	NOT NULL,
+		"id"			text	NOT NULL,
+		"state_root"	text	NOT NULL,
+		"event"			text	NOT NULL,
+		"address"		text	NOT NULL,
+		"code"			text	NOT NULL,
+		"prev_code"		text,
+		"time"			timestamptz,
+
+		PRIMARY KEY ("height", "id", "state_root", "event")
+	);
+	CREATE INDEX IF NOT EXISTS actor_lifecycle_events_time_idx ON {{ .SchemaName | default "public"}}.actor_lifecycle_events USING btree ("time" DESC);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.actor_lifecycle_events IS 'Creation, deletion and code upgrades of actors.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_lifecycle_events.height IS 'Epoch at which the event is visible in the state tree.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_lifecycle_events.id IS 'ID address of the actor.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_lifecycle_events.state_root IS 'CID of the parent state root in which the event is visible.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_lifecycle_events.event IS 'Type of event: CREATED, DELETED or UPGRADED.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_lifecycle_events.address IS 'Robust address of the actor, or its ID address when it has no known robust address.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_lifecycle_events.code IS 'Human readable identifier for the code of the actor after the event, or before it was deleted.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_lifecycle_events.prev_code IS 'Human readable identifier for the code of the actor before an upgrade. Null for other events.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_lifecycle_events."time" IS 'Wallclock time of the epoch of this row, derived from the minimum timestamp of the blocks in the tipset at that height. Null unless timestamp enrichment was enabled when the row was persisted.';
+`)
+}
@@ -0,0 +1,51 @@
+package v1
+
+// Schema version 1 adds network versions and upgrades
+
+func init() {
+	patches.Register(
+		15,
+		`
+	-- ----------------------------------------------------------------
+	-- Name: network_versions
+	-- Model: chain.NetworkVersion
+	-- Growth: One row for each network upgrade
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.network_versions (
+		"height"				bigint	NOT NULL,
+		"state_root"			text	NOT NULL,
+		"network_version"		bigint	NOT NULL,
+		"actors_version"		bigint	NOT NULL,
+		"state_tree_version"	bigint	NOT NULL,
+		"time"					timestamptz,
+
+		PRIMARY KEY ("height", "state_root")
+	);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.network_versions IS 'Versions of the network, actors and state tree in effect from an epoch onwards. A row is recorded at genesis and at each epoch where one of the versions changed.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.network_versions.height IS 'Epoch from which the versions are in effect.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.network_versions.state_root IS 'CID of the parent state root of the tipset at this epoch.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.network_versions.network_version IS 'Network version in effect.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.network_versions.actors_version IS 'Version of the builtin actors used by the network version.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.network_versions.state_tree_version IS 'Version of the state tree at the parent state root.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.network_versions."time" IS 'Wallclock time of the epoch of this row, derived from the minimum timestamp of the blocks in the tipset at that height. Null unless timestamp enrichment was enabled when the row was persisted.';
+
+	-- ----------------------------------------------------------------
+	-- Name: network_upgrades
+	-- Model: chain.NetworkUpgrade
+	-- Growth: One row for each upgrade in the schedule of the network
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.network_upgrades (
+		"height"			bigint	NOT NULL,
+		"network_version"	bigint	NOT NULL,
+		"actors_version"	bigint	NOT NULL,
+
+		PRIMARY KEY ("height", "network_version")
+	);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.network_upgrades IS 'Upgrade schedule of the network lily was built for. Some upgrades, such as Refuel and Liftoff, keep the network version of the upgrade before them.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.network_upgrades.network_version IS 'Network version in effect after the upgrade.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.network_upgrades.height IS 'Last epoch before the upgrade takes effect. Negative when the upgrade is disabled.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.network_upgrades.actors_version IS 'Version of the builtin actors used after the upgrade.';
+`)
+}
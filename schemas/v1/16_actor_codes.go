@@ -0,0 +1,51 @@
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin"
+)
+
+// Schema version 1 adds a reference table of actor codes
+
+func init() {
+	patches.Register(
+		16,
+		`
+	-- ----------------------------------------------------------------
+	-- Name: actor_codes
+	-- Model: common.ActorCode
+	-- Growth: One row for each builtin actor in each actors version
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.actor_codes (
+		"code"		text	NOT NULL,
+		"name"		text	NOT NULL,
+		"family"	text	NOT NULL,
+		"version"	bigint	NOT NULL,
+
+		PRIMARY KEY ("code")
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS actor_codes_name_idx ON {{ .SchemaName | default "public"}}.actor_codes USING btree (name);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.actor_codes IS 'Codes of the builtin actors known to lily. Refreshed each time the lily daemon starts.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_codes.code IS 'CID of the actor code, as found in actor_states.code.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_codes.name IS 'Human readable identifier for the actor code, as found in actors.code. For example fil/3/storageminer.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_codes.family IS 'Family of the actor, which is the same across actors versions. For example storageminer.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_codes.version IS 'Version of the actors the code belongs to. Note that codes of version 0 actors are named fil/1/...';
+`+actorCodesInsert())
+}
+
+// actorCodesInsert returns a statement populating actor_codes with the codes known at the time of migration.
+func actorCodesInsert() string {
+	var values []string
+	for _, c := range builtin.AllActorCodes() {
+		values = append(values, fmt.Sprintf("('%s', '%s', '%s', %d)", c.Code, c.Name, c.Family, c.Version))
+	}
+
+	return `
+	INSERT INTO {{ .SchemaName | default "public"}}.actor_codes ("code", "name", "family", "version") VALUES
+		` + strings.Join(values, ",\n\t\t") + `
+	ON CONFLICT ("code") DO UPDATE SET "name" = EXCLUDED."name", "family" = EXCLUDED."family", "version" = EXCLUDED."version";
+`
+}
@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/exitcode"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin"
+)
+
+// Schema version 1 adds method and exit code names
+
+func init() {
+	patches.Register(
+		17,
+		`
+	ALTER TABLE {{ .SchemaName | default "public"}}.messages ADD COLUMN IF NOT EXISTS "method_name" text;
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.messages.method_name IS 'Name of the method invoked on the recipient actor. Null when the code of the recipient actor is not known.';
+
+	ALTER TABLE {{ .SchemaName | default "public"}}.receipts ADD COLUMN IF NOT EXISTS "exit_code_name" text;
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.receipts.exit_code_name IS 'Symbolic name of the exit code, taking into account exit codes specific to the recipient actor. Null when the exit code is not known.';
+
+	ALTER TABLE {{ .SchemaName | default "public"}}.internal_messages ADD COLUMN IF NOT EXISTS "method_name" text;
+	ALTER TABLE {{ .SchemaName | default "public"}}.internal_messages ADD COLUMN IF NOT EXISTS "exit_code_name" text;
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.internal_messages.method_name IS 'Name of the method invoked on the recipient actor. Null when the code of the recipient actor is not known.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.internal_messages.exit_code_name IS 'Symbolic name of the exit code, taking into account exit codes specific to the recipient actor. Null when the exit code is not known.';
+
+	-- ----------------------------------------------------------------
+	-- Name: actor_methods
+	-- Model: common.ActorMethod
+	-- Growth: One row for each method of each builtin actor in each actors version
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.actor_methods (
+		"code"		text	NOT NULL,
+		"method"	bigint	NOT NULL,
+		"name"		text	NOT NULL,
+
+		PRIMARY KEY ("code", "method")
+	);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.actor_methods IS 'Names of the methods of the builtin actors known to lily. Refreshed each time the lily daemon starts.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_methods.code IS 'CID of the actor code, as found in actor_codes.code.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_methods.method IS 'Method number, as found in messages.method.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_methods.name IS 'Name of the method.';
+
+	-- ----------------------------------------------------------------
+	-- Name: actor_exit_codes
+	-- Model: common.ActorExitCode
+	-- Growth: One row for each known exit code of each builtin actor in each actors version
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.actor_exit_codes (
+		"code"		text	NOT NULL,
+		"exit_code"	bigint	NOT NULL,
+		"name"		text	NOT NULL,
+
+		PRIMARY KEY ("code", "exit_code")
+	);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.actor_exit_codes IS 'Symbolic names of the exit codes that may be returned by the builtin actors known to lily, including exit codes set by the vm. Refreshed each time the lily daemon starts.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_exit_codes.code IS 'CID of the actor code, as found in actor_codes.code.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_exit_codes.exit_code IS 'Exit code, as found in receipts.exit_code.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.actor_exit_codes.name IS 'Symbolic name of the exit code.';
+`+actorMethodsInsert()+actorExitCodesInsert())
+}
+
+// actorMethodsInsert returns a statement populating actor_methods with the methods known at the time of migration.
+func actorMethodsInsert() string {
+	var values []string
+	for _, c := range builtin.AllActorCodes() {
+		names := builtin.MethodNames(c.Code)
+		methods := make([]int, 0, len(names))
+		for m := range names {
+			methods = append(methods, int(m))
+		}
+		sort.Ints(methods)
+		for _, m := range methods {
+			values = append(values, fmt.Sprintf("('%s', %d, '%s')", c.Code, m, names[abi.MethodNum(m)]))
+		}
+	}
+
+	return `
+	INSERT INTO {{ .SchemaName | default "public"}}.actor_methods ("code", "method", "name") VALUES
+		` + strings.Join(values, ",\n\t\t") + `
+	ON CONFLICT ("code", "method") DO UPDATE SET "name" = EXCLUDED."name";
+`
+}
+
+// actorExitCodesInsert returns a statement populating actor_exit_codes with the exit codes known at the time of
+// migration.
+func actorExitCodesInsert() string {
+	var values []string
+	for _, c := range builtin.AllActorCodes() {
+		names := builtin.ExitCodeNames(c.Code)
+		codes := make([]int, 0, len(names))
+		for ec := range names {
+			codes = append(codes, int(ec))
+		}
+		sort.Ints(codes)
+		for _, ec := range codes {
+			values = append(values, fmt.Sprintf("('%s', %d, '%s')", c.Code, ec, names[exitcode.ExitCode(ec)]))
+		}
+	}
+
+	return `
+	INSERT INTO {{ .SchemaName | default "public"}}.actor_exit_codes ("code", "exit_code", "name") VALUES
+		` + strings.Join(values, ",\n\t\t") + `
+	ON CONFLICT ("code", "exit_code") DO UPDATE SET "name" = EXCLUDED."name";
+`
+}
@@ -0,0 +1,74 @@
+package v1
+
+// Schema version 1 adds sender nonce summaries and nonce replacements
+
+func init() {
+	patches.Register(
+		18,
+		`
+	-- ----------------------------------------------------------------
+	-- Name: message_sender_nonces
+	-- Model: derived.SenderNonces
+	-- Growth: One row for each sender with messages executed in a tipset
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.message_sender_nonces (
+		"height"			bigint	NOT NULL,
+		"state_root"		text	NOT NULL,
+		"sender"			text	NOT NULL,
+		"message_count"		bigint	NOT NULL,
+		"first_nonce"		numeric	NOT NULL,
+		"last_nonce"		numeric	NOT NULL,
+		"expected_nonce"	numeric	NOT NULL,
+		"gap"				bigint	NOT NULL,
+		"contiguous"		boolean	NOT NULL,
+		"time"				timestamptz,
+
+		PRIMARY KEY ("height", "state_root", "sender")
+	);
+	CREATE INDEX IF NOT EXISTS message_sender_nonces_sender_idx ON {{ .SchemaName | default "public"}}.message_sender_nonces USING hash ("sender");
+	CREATE INDEX IF NOT EXISTS message_sender_nonces_time_idx ON {{ .SchemaName | default "public"}}.message_sender_nonces USING btree ("time" DESC);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.message_sender_nonces IS 'Nonces of the messages executed for each sender in a tipset.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_sender_nonces.height IS 'Epoch of the tipset containing the messages.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_sender_nonces.state_root IS 'CID of the parent state root of the tipset containing the messages.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_sender_nonces.sender IS 'ID address of the sender of the messages, or the address used by the messages if it could not be resolved.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_sender_nonces.message_count IS 'Number of distinct messages from the sender executed in the tipset.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_sender_nonces.first_nonce IS 'Lowest nonce of the messages from the sender.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_sender_nonces.last_nonce IS 'Highest nonce of the messages from the sender.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_sender_nonces.expected_nonce IS 'Nonce of the sender before the tipset was executed. Equal to first_nonce when the sender could not be loaded.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_sender_nonces.gap IS 'Difference between first_nonce and expected_nonce.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_sender_nonces.contiguous IS 'True when the distinct nonces of the messages form a contiguous sequence from first_nonce to last_nonce.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_sender_nonces."time" IS 'Wallclock time of the epoch of this row, derived from the minimum timestamp of the blocks in the tipset at that height. Null unless timestamp enrichment was enabled when the row was persisted.';
+
+	-- ----------------------------------------------------------------
+	-- Name: message_nonce_replacements
+	-- Model: derived.NonceReplacement
+	-- Growth: One row for each message that replaced a message executed in a reverted tipset
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.message_nonce_replacements (
+		"height"			bigint	NOT NULL,
+		"state_root"		text	NOT NULL,
+		"sender"			text	NOT NULL,
+		"nonce"				numeric	NOT NULL,
+		"message"			text	NOT NULL,
+		"replaced_cid"		text	NOT NULL,
+		"replaced_height"	bigint	NOT NULL,
+		"replaced_tip_set"	text	NOT NULL,
+		"time"				timestamptz,
+
+		PRIMARY KEY ("height", "state_root", "sender", "nonce")
+	);
+	CREATE INDEX IF NOT EXISTS message_nonce_replacements_time_idx ON {{ .SchemaName | default "public"}}.message_nonce_replacements USING btree ("time" DESC);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.message_nonce_replacements IS 'Messages executed with the same sender and nonce as a different message executed in a tipset that was later reverted. Best effort: only recorded by watches, which see reverted tipsets, for replacements seen by the same job since it last started.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_nonce_replacements.height IS 'Epoch of the tipset containing the replacing message.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_nonce_replacements.state_root IS 'CID of the parent state root of the tipset containing the replacing message.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_nonce_replacements.sender IS 'ID address of the sender of both messages, or the address used by the messages if it could not be resolved.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_nonce_replacements.nonce IS 'Nonce shared by both messages.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_nonce_replacements.message IS 'CID of the replacing message.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_nonce_replacements.replaced_cid IS 'CID of the message executed in the reverted tipset.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_nonce_replacements.replaced_height IS 'Epoch of the reverted tipset.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_nonce_replacements.replaced_tip_set IS 'Key of the reverted tipset.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.message_nonce_replacements."time" IS 'Wallclock time of the epoch of this row, derived from the minimum timestamp of the blocks in the tipset at that height. Null unless timestamp enrichment was enabled when the row was persisted.';
+`)
+}
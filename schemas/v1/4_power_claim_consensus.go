@@ -0,0 +1,28 @@
+package v1
+
+// Schema version 1 adds power actor claim consensus eligibility
+
+func init() {
+	patches.Register(
+		4,
+		`
+	-- ----------------------------------------------------------------
+	-- Name: power_actor_claim_consensus
+	-- Model: power.PowerActorClaimConsensus
+	-- Growth: About 7 rows per epoch
+	-- ----------------------------------------------------------------
+	CREATE TABLE {{ .SchemaName | default "public"}}.power_actor_claim_consensus (
+		height 						bigint NOT NULL,
+		miner_id 					text NOT NULL,
+		state_root 					text NOT NULL,
+		meets_consensus_minimum 	boolean NOT NULL
+	);
+	ALTER TABLE ONLY {{ .SchemaName | default "public"}}.power_actor_claim_consensus ADD CONSTRAINT power_actor_claim_consensus_pkey PRIMARY KEY (height, miner_id, state_root);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.power_actor_claim_consensus IS 'Consensus eligibility of miners whose power claim changed, and of all miners when the consensus minimum may have changed. Network wide miner counts are recorded in chain_powers.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.power_actor_claim_consensus.height IS 'Epoch at which the claim or the consensus minimum changed.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.power_actor_claim_consensus.miner_id IS 'Address of miner making the claim.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.power_actor_claim_consensus.state_root IS 'CID of the parent state root at this epoch.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.power_actor_claim_consensus.meets_consensus_minimum IS 'True if the miner''s nominal power meets the minimum required to participate in consensus.';
+`)
+}
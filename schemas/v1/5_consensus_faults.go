@@ -0,0 +1,53 @@
+package v1
+
+// Schema version 1 adds consensus fault reports
+
+func init() {
+	patches.Register(
+		5,
+		`
+	CREATE TYPE {{ .SchemaName | default "public"}}.consensus_fault_type AS ENUM (
+		'DOUBLE_FORK_MINING',
+		'TIME_OFFSET_MINING',
+		'PARENT_GRINDING'
+	);
+
+	-- ----------------------------------------------------------------
+	-- Name: consensus_faults
+	-- Model: consensusfaults.ConsensusFault
+	-- Growth: Rare, one row per successful fault report
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.consensus_faults (
+		"height"				bigint  NOT NULL,
+		"state_root"			text    NOT NULL,
+		"message"				text    NOT NULL,
+
+		"reporter"				text    NOT NULL,
+		"miner"					text    NOT NULL,
+		"fault_epoch"			bigint  NOT NULL,
+		"fault_type"			{{ .SchemaName | default "public"}}.consensus_fault_type NOT NULL,
+
+		"block_header1"			text    NOT NULL,
+		"block_header2"			text    NOT NULL,
+		"block_header_extra"	text,
+
+		"penalty"				numeric NOT NULL,
+		"reporter_reward"		numeric NOT NULL,
+
+		PRIMARY KEY ("height", "state_root", "message")
+	);
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.consensus_faults IS 'Consensus faults successfully reported to miner actors.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.consensus_faults.height IS 'Epoch at which the fault report message was executed.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.consensus_faults.state_root IS 'CID of the parent state root at this epoch.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.consensus_faults.message IS 'CID of the ReportConsensusFault message.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.consensus_faults.reporter IS 'Address of the actor that reported the fault.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.consensus_faults.miner IS 'Address of the miner that committed the fault.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.consensus_faults.fault_epoch IS 'Epoch at which the fault occurred.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.consensus_faults.fault_type IS 'Type of consensus fault.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.consensus_faults.block_header1 IS 'CID of the first block header submitted as evidence.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.consensus_faults.block_header2 IS 'CID of the second block header submitted as evidence.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.consensus_faults.block_header_extra IS 'CID of the extra block header submitted as evidence of parent grinding, NULL for other fault types.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.consensus_faults.penalty IS 'Amount of attoFIL burnt by the miner while processing the report. Includes any existing fee debt the miner repaid alongside the fault penalty.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.consensus_faults.reporter_reward IS 'Amount of attoFIL paid by the miner to the reporter.';
+`)
+}
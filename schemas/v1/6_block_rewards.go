@@ -0,0 +1,38 @@
+package v1
+
+// Schema version 1 adds per block reward attribution
+
+func init() {
+	patches.Register(
+		6,
+		`
+	-- ----------------------------------------------------------------
+	-- Name: block_rewards
+	-- Model: derived.BlockReward
+	-- Growth: About 5 rows per epoch
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.block_rewards (
+		"height"		bigint  NOT NULL,
+		"block"			text    NOT NULL,
+		"state_root"	text    NOT NULL,
+		"miner"			text    NOT NULL,
+		"win_count"		bigint  NOT NULL,
+		"gas_reward"	numeric NOT NULL,
+		"penalty"		numeric NOT NULL,
+		"reward"		numeric NOT NULL,
+		"total_reward"	numeric NOT NULL,
+
+		PRIMARY KEY ("height", "block", "state_root")
+	);
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.block_rewards IS 'Rewards paid by the reward actor to the miner of each block whose messages were executed.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.block_rewards.height IS 'Epoch of the tipset containing the block.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.block_rewards.block IS 'CID of the block that earned the reward.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.block_rewards.state_root IS 'CID of the parent state root at this epoch.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.block_rewards.miner IS 'Address of the miner that mined the block.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.block_rewards.win_count IS 'Number of election wins claimed by the block.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.block_rewards.gas_reward IS 'Gas reward in attoFIL earned from message inclusion in the block.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.block_rewards.penalty IS 'Penalty in attoFIL charged to the miner for including invalid messages in the block.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.block_rewards.reward IS 'Block reward in attoFIL minted by the reward actor for the block, excluding the gas reward.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.block_rewards.total_reward IS 'Total attoFIL transferred by the reward actor for the block, the sum of the minted reward and the gas reward.';
+`)
+}
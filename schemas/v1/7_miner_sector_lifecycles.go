@@ -0,0 +1,51 @@
+package v1
+
+// Schema version 1 adds derived miner sector lifecycles
+
+func init() {
+	patches.Register(
+		7,
+		`
+	-- ----------------------------------------------------------------
+	-- Name: miner_sector_lifecycles
+	-- Model: miner.MinerSectorLifecycle
+	-- Growth: One row per sector, updated as sector events occur
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.miner_sector_lifecycles (
+		"miner_id"				text   NOT NULL,
+		"sector_id"				bigint NOT NULL,
+
+		"pre_commit_epoch"		bigint,
+		"prove_commit_epoch"	bigint,
+		"first_fault_epoch"		bigint,
+		"last_recovery_epoch"	bigint,
+		"last_extension_epoch"	bigint,
+		"termination_epoch"		bigint,
+		"expiration_epoch"		bigint,
+
+		"sealing_duration"		bigint,
+		"active_duration"		bigint,
+
+		"last_event"			{{ .SchemaName | default "public"}}.miner_sector_event_type NOT NULL,
+		"last_event_height"		bigint NOT NULL,
+
+		PRIMARY KEY ("miner_id", "sector_id")
+	);
+	CREATE INDEX IF NOT EXISTS miner_sector_lifecycles_last_event_height_idx ON {{ .SchemaName | default "public"}}.miner_sector_lifecycles USING btree (last_event_height DESC);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.miner_sector_lifecycles IS 'Lifecycle of each sector derived from the events recorded in miner_sector_events.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.miner_sector_lifecycles.miner_id IS 'Address of the miner who owns the sector.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.miner_sector_lifecycles.sector_id IS 'Numeric identifier of the sector.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.miner_sector_lifecycles.pre_commit_epoch IS 'Epoch at which the sector was precommitted.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.miner_sector_lifecycles.prove_commit_epoch IS 'Epoch at which the sector was proven and added to the miner''s sectors.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.miner_sector_lifecycles.first_fault_epoch IS 'Epoch at which the sector first became faulty.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.miner_sector_lifecycles.last_recovery_epoch IS 'Epoch at which the sector most recently recovered from a fault.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.miner_sector_lifecycles.last_extension_epoch IS 'Epoch at which the sector''s expiration was most recently extended.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.miner_sector_lifecycles.termination_epoch IS 'Epoch at which the sector was terminated early.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.miner_sector_lifecycles.expiration_epoch IS 'Epoch at which the sector expired.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.miner_sector_lifecycles.sealing_duration IS 'Number of epochs between precommit and prove-commit.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.miner_sector_lifecycles.active_duration IS 'Number of epochs between prove-commit and termination or expiration.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.miner_sector_lifecycles.last_event IS 'Most recent event that occurred for the sector.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.miner_sector_lifecycles.last_event_height IS 'Epoch of the most recent event that occurred for the sector.';
+`)
+}
@@ -0,0 +1,61 @@
+package v1
+
+// Schema version 1 adds parsed message return values
+
+func init() {
+	patches.Register(
+		8,
+		`
+	-- ----------------------------------------------------------------
+	-- Name: parsed_receipts
+	-- Model: messages.ParsedReceipt
+	-- Growth: One row per successful executed message with a return value
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.parsed_receipts (
+		"height"		bigint NOT NULL,
+		"message"		text   NOT NULL,
+		"state_root"	text   NOT NULL,
+		"idx"			bigint NOT NULL,
+		"exit_code"		bigint NOT NULL,
+		"method"		text   NOT NULL,
+		"return"		jsonb,
+
+		PRIMARY KEY ("height", "message", "state_root")
+	);
+	CREATE INDEX IF NOT EXISTS parsed_receipts_method_idx ON {{ .SchemaName | default "public"}}.parsed_receipts USING hash (method);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.parsed_receipts IS 'Return values of successfully executed messages parsed to extract useful information.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.parsed_receipts.height IS 'Epoch the message was executed and receipt generated.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.parsed_receipts.message IS 'CID of the message this receipt belongs to.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.parsed_receipts.state_root IS 'CID of the parent state root at this epoch.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.parsed_receipts.idx IS 'Index of message indicating execution order.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.parsed_receipts.exit_code IS 'The exit code that was returned as a result of executing the message.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.parsed_receipts.method IS 'The name of the method that was invoked on the recipient actor.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.parsed_receipts."return" IS 'Method return value parsed and serialized as JSON in the same way as message params. Return values of known methods are serialized as objects keyed by field name, others are decoded generically.';
+
+	-- ----------------------------------------------------------------
+	-- Name: internal_parsed_receipts
+	-- Model: messages.InternalParsedReceipt
+	-- Growth: One row per successful internal message with a return value
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.internal_parsed_receipts (
+		"height"		bigint NOT NULL,
+		"cid"			text   NOT NULL,
+		"state_root"	text   NOT NULL,
+		"exit_code"		bigint NOT NULL,
+		"method"		text   NOT NULL,
+		"return"		jsonb,
+
+		PRIMARY KEY ("height", "cid")
+	);
+	CREATE INDEX IF NOT EXISTS internal_parsed_receipts_method_idx ON {{ .SchemaName | default "public"}}.internal_parsed_receipts USING hash (method);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.internal_parsed_receipts IS 'Return values of successfully executed internal messages parsed to extract useful information.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.internal_parsed_receipts.height IS 'Epoch this message was executed at.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.internal_parsed_receipts.cid IS 'CID of the message.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.internal_parsed_receipts.state_root IS 'CID of the parent state root at which this message was executed.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.internal_parsed_receipts.exit_code IS 'The exit code that was returned as a result of executing the message.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.internal_parsed_receipts.method IS 'The name of the method that was invoked on the recipient actor.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.internal_parsed_receipts."return" IS 'Method return value parsed and serialized as JSON in the same way as message params. Return values of known methods are serialized as objects keyed by field name, others are decoded generically.';
+`)
+}
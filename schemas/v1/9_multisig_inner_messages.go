@@ -0,0 +1,45 @@
+package v1
+
+// Schema version 1 adds decoded calls proposed to multisig actors
+
+func init() {
+	patches.Register(
+		9,
+		`
+	-- ----------------------------------------------------------------
+	-- Name: multisig_inner_messages
+	-- Model: messages.MultisigInnerMessage
+	-- Growth: About 1 row per successful multisig propose, approve or cancel message
+	-- ----------------------------------------------------------------
+	CREATE TABLE IF NOT EXISTS {{ .SchemaName | default "public"}}.multisig_inner_messages (
+		"height"			bigint  NOT NULL,
+		"cid"				text    NOT NULL,
+		"depth"				bigint  NOT NULL,
+		"state_root"		text    NOT NULL,
+		"multisig_id"		text    NOT NULL,
+		"transaction_id"	bigint  NOT NULL,
+		"applied"			boolean NOT NULL,
+		"to"				text    NOT NULL,
+		"value"				numeric NOT NULL,
+		"method"			text    NOT NULL,
+		"params"			jsonb,
+
+		PRIMARY KEY ("height", "cid", "depth")
+	);
+	CREATE INDEX IF NOT EXISTS multisig_inner_messages_to_idx ON {{ .SchemaName | default "public"}}.multisig_inner_messages USING hash ("to");
+	CREATE INDEX IF NOT EXISTS multisig_inner_messages_method_idx ON {{ .SchemaName | default "public"}}.multisig_inner_messages USING hash (method);
+
+	COMMENT ON TABLE {{ .SchemaName | default "public"}}.multisig_inner_messages IS 'Calls proposed to, approved by or cancelled on multisig actors, decoded from successful propose, approve and cancel messages.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.multisig_inner_messages.height IS 'Epoch this message was executed at.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.multisig_inner_messages.cid IS 'CID of the propose, approve or cancel message.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.multisig_inner_messages.depth IS 'Number of multisig actors the call passed through before this one. Zero for the call carried by the message itself.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.multisig_inner_messages.state_root IS 'CID of the parent state root at which this message was executed.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.multisig_inner_messages.multisig_id IS 'Address of the multisig actor the call was proposed to.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.multisig_inner_messages.transaction_id IS 'Identifier of the multisig transaction holding the call.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.multisig_inner_messages.applied IS 'Whether the call had enough approvals to be executed.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.multisig_inner_messages."to" IS 'Address of the actor the call is sent to.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.multisig_inner_messages.value IS 'Amount of FIL (in attoFIL) transferred by the call.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.multisig_inner_messages.method IS 'The name of the method invoked on the actor the call is sent to.';
+	COMMENT ON COLUMN {{ .SchemaName | default "public"}}.multisig_inner_messages.params IS 'Method parameters of the call parsed and serialized as a JSON object.';
+`)
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin"
+	"github.com/filecoin-project/lily/model/actors/common"
+)
+
+// ActorCodes returns the reference data describing the codes of all builtin actors known to this version of lily.
+func ActorCodes() common.ActorCodeList {
+	codes := builtin.AllActorCodes()
+	out := make(common.ActorCodeList, 0, len(codes))
+	for _, c := range codes {
+		out = append(out, &common.ActorCode{
+			Code:    c.Code.String(),
+			Name:    c.Name,
+			Family:  c.Family,
+			Version: int64(c.Version),
+		})
+	}
+	return out
+}
+
+// ActorMethods returns the reference data naming the methods of all builtin actors known to this version of lily.
+func ActorMethods() common.ActorMethodList {
+	var out common.ActorMethodList
+	for _, c := range builtin.AllActorCodes() {
+		start := len(out)
+		for method, name := range builtin.MethodNames(c.Code) {
+			out = append(out, &common.ActorMethod{
+				Code:   c.Code.String(),
+				Method: uint64(method),
+				Name:   name,
+			})
+		}
+		methods := out[start:]
+		sort.Slice(methods, func(i, j int) bool { return methods[i].Method < methods[j].Method })
+	}
+	return out
+}
+
+// ActorExitCodes returns the reference data naming the exit codes of all builtin actors known to this version of lily.
+func ActorExitCodes() common.ActorExitCodeList {
+	var out common.ActorExitCodeList
+	for _, c := range builtin.AllActorCodes() {
+		start := len(out)
+		for ec, name := range builtin.ExitCodeNames(c.Code) {
+			out = append(out, &common.ActorExitCode{
+				Code:     c.Code.String(),
+				ExitCode: int64(ec),
+				Name:     name,
+			})
+		}
+		codes := out[start:]
+		sort.Slice(codes, func(i, j int) bool { return codes[i].ExitCode < codes[j].ExitCode })
+	}
+	return out
+}
+
+// RefreshReferenceData brings the actor_codes, actor_methods and actor_exit_codes tables up to date with the builtin
+// actors known to this version of lily.
+func (d *Database) RefreshReferenceData(ctx context.Context) error {
+	return d.PersistBatch(ctx, ActorCodes(), ActorMethods(), ActorExitCodes())
+}
+
+// RefreshReferenceData refreshes the reference data of every database in the catalog, connecting to them if needed.
+// It is called when the daemon starts. Failures are logged and do not prevent the remaining databases from being
+// refreshed.
+func (c *Catalog) RefreshReferenceData(ctx context.Context) {
+	for name, s := range c.storages {
+		db, ok := s.(*Database)
+		if !ok {
+			continue
+		}
+		if !db.IsConnected(ctx) {
+			if err := db.Connect(ctx); err != nil {
+				log.Warnw("failed to connect to database to refresh reference data", "storage", name, "error", err)
+				continue
+			}
+		}
+		c.refreshReferenceData(ctx, name, db)
+	}
+}
+
+// refreshReferenceData refreshes the reference data of the database with the given name unless it has already been
+// refreshed. Databases that could not be refreshed when the daemon started are retried when a job connects to them.
+// Failures are logged and do not prevent the database from being used.
+func (c *Catalog) refreshReferenceData(ctx context.Context, name string, db *Database) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	if c.refreshed[name] {
+		return
+	}
+	if err := db.RefreshReferenceData(ctx); err != nil {
+		log.Warnw("failed to refresh reference data", "storage", name, "error", err)
+		return
+	}
+	c.refreshed[name] = true
+}
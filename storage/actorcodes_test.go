@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"testing"
+
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
+	builtin5 "github.com/filecoin-project/specs-actors/v5/actors/builtin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActorCodes(t *testing.T) {
+	codes := ActorCodes()
+	assert.Len(t, codes, 11*5)
+
+	seen := map[string]bool{}
+	for _, c := range codes {
+		assert.False(t, seen[c.Code], "duplicate code %s", c.Code)
+		seen[c.Code] = true
+
+		assert.NotEqual(t, "<unknown>", c.Name)
+		assert.NotEqual(t, "<unknown>", c.Family)
+		assert.Contains(t, c.Name, "/"+c.Family)
+	}
+}
+
+func TestActorMethodsAndExitCodes(t *testing.T) {
+	minerCode := builtin5.StorageMinerActorCodeID.String()
+	paychCode := builtin0.PaymentChannelActorCodeID.String()
+
+	methods := map[string]map[uint64]string{}
+	for _, m := range ActorMethods() {
+		if methods[m.Code] == nil {
+			methods[m.Code] = map[uint64]string{}
+		}
+		methods[m.Code][m.Method] = m.Name
+	}
+	assert.Equal(t, "Send", methods[minerCode][0])
+	assert.Equal(t, "PreCommitSector", methods[minerCode][6])
+	assert.Equal(t, "Send", methods[paychCode][0])
+
+	exitCodes := map[string]map[int64]string{}
+	for _, ec := range ActorExitCodes() {
+		if exitCodes[ec.Code] == nil {
+			exitCodes[ec.Code] = map[int64]string{}
+		}
+		exitCodes[ec.Code][ec.ExitCode] = ec.Name
+	}
+	assert.Equal(t, "SysErrOutOfGas", exitCodes[minerCode][7])
+	assert.Equal(t, "ErrBalanceInvariantBroken", exitCodes[minerCode][1000])
+	assert.Equal(t, "ErrChannelStateUpdateAfterSettled", exitCodes[paychCode][32])
+	assert.Empty(t, exitCodes[minerCode][32])
+}
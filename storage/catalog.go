@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/filecoin-project/lily/config"
 	"github.com/filecoin-project/lily/model"
@@ -18,7 +19,8 @@ type Connector interface {
 
 func NewCatalog(cfg config.StorageConf) (*Catalog, error) {
 	c := &Catalog{
-		storages: make(map[string]model.Storage),
+		storages:  make(map[string]model.Storage),
+		refreshed: make(map[string]bool),
 	}
 
 	for name, sc := range cfg.Postgresql {
@@ -39,6 +41,7 @@ func NewCatalog(cfg config.StorageConf) (*Catalog, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to create postgresql storage %q: %w", name, err)
 		}
+		db.EnrichTimestamps = sc.EnrichTimestamps
 
 		c.storages[name] = db
 	}
@@ -68,12 +71,24 @@ func NewCatalog(cfg config.StorageConf) (*Catalog, error) {
 
 	}
 
+	for name, sc := range cfg.Publish {
+		if _, exists := c.storages[name]; exists {
+			return nil, fmt.Errorf("duplicate storage name: %q", name)
+		}
+		log.Debugw("registering storage", "name", name, "type", "publish")
+
+		c.storages[name] = NewPublishStorage(sc.BufferSize)
+	}
+
 	return c, nil
 }
 
 // A Catalog holds a list of pre-configured storage systems and can open them when requested.
 type Catalog struct {
 	storages map[string]model.Storage
+
+	refreshMu sync.Mutex      // guards refreshed
+	refreshed map[string]bool // names of databases whose reference data has been refreshed
 }
 
 // Connect returns a storage that is ready for use. If name is empty, a null storage will be returned
@@ -104,9 +119,28 @@ func (c *Catalog) Connect(ctx context.Context, name string, md Metadata) (model.
 		}
 	}
 
+	if db, ok := s.(*Database); ok {
+		c.refreshReferenceData(ctx, name, db)
+	}
+
 	return s, nil
 }
 
+// Publisher returns the publishing storage with the given name, used to subscribe to the models persisted by jobs
+// using it.
+func (c *Catalog) Publisher(name string) (*PublishStorage, error) {
+	s, exists := c.storages[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown storage: %q", name)
+	}
+
+	ps, ok := s.(*PublishStorage)
+	if !ok {
+		return nil, xerrors.Errorf("storage %q does not publish: storage type (%T) is unsupported", name, s)
+	}
+	return ps, nil
+}
+
 type StorageWithMetadata interface {
 	// WithMetadata returns a storage based configured with the supplied metadata
 	WithMetadata(Metadata) model.Storage
@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/go-pg/pg/v10/orm"
+
+	"github.com/filecoin-project/lily/model"
+)
+
+// DefaultPublishBufferSize is the number of batches buffered for each subscriber when none is configured.
+const DefaultPublishBufferSize = 100
+
+// A Batch holds the models of a single table persisted together by a job.
+type Batch struct {
+	Job    string        // name of the job that persisted the models
+	Table  string        // name of the table the models belong to
+	Models []interface{} // the models, encoded as JSON when sent to subscribers
+}
+
+var (
+	_ model.Storage       = (*PublishStorage)(nil)
+	_ StorageWithMetadata = (*PublishStorage)(nil)
+)
+
+// A PublishStorage publishes the models persisted by a job to subscribers instead of writing them. Subscribers
+// receive a Batch for each table in each call to PersistBatch, in the order they were persisted. Models persisted
+// while a job has no subscribers are discarded.
+//
+// Publishing never blocks the job. Each subscriber has a buffer of a fixed number of batches and a subscriber whose
+// buffer is full when a batch is published is disconnected by closing its channel. Slow subscribers therefore cannot
+// stall the job or silently miss batches: a disconnected subscriber can subscribe again and backfill the heights it
+// missed with a walk.
+type PublishStorage struct {
+	bufferSize int
+	version    model.Version
+	jobName    string
+	subs       *subscriptions // shared by all copies of the storage made by WithMetadata
+}
+
+func NewPublishStorage(bufferSize int) *PublishStorage {
+	if bufferSize <= 0 {
+		bufferSize = DefaultPublishBufferSize
+	}
+	return &PublishStorage{
+		bufferSize: bufferSize,
+		version:    LatestSchemaVersion(),
+		subs: &subscriptions{
+			byJob: map[string]map[*subscriber]struct{}{},
+		},
+	}
+}
+
+// WithMetadata returns a copy of the storage that publishes to subscribers of the job named in the metadata.
+func (p *PublishStorage) WithMetadata(md Metadata) model.Storage {
+	c := *p
+	c.jobName = md.JobName
+	return &c
+}
+
+// Subscribe returns a channel that receives the batches published by the named job for the given tables, or for all
+// tables if none are given. The channel is closed when the context is done or when the subscriber falls more than
+// the storage's buffer size behind.
+func (p *PublishStorage) Subscribe(ctx context.Context, job string, tables []string) <-chan *Batch {
+	sub := &subscriber{
+		tables: make(map[string]bool, len(tables)),
+		ch:     make(chan *Batch, p.bufferSize),
+	}
+	for _, t := range tables {
+		sub.tables[t] = true
+	}
+
+	p.subs.add(job, sub)
+	go func() {
+		<-ctx.Done()
+		p.subs.remove(job, sub)
+	}()
+	return sub.ch
+}
+
+func (p *PublishStorage) PersistBatch(ctx context.Context, ps ...model.Persistable) error {
+	if !p.subs.has(p.jobName) {
+		return nil
+	}
+
+	b := &publishBatch{tables: map[string][]interface{}{}}
+	for _, m := range ps {
+		if err := m.Persist(ctx, b, p.version); err != nil {
+			return err
+		}
+	}
+
+	tables := make([]string, 0, len(b.tables))
+	for t := range b.tables {
+		tables = append(tables, t)
+	}
+	sort.Strings(tables)
+	for _, t := range tables {
+		p.subs.publish(&Batch{
+			Job:    p.jobName,
+			Table:  t,
+			Models: b.tables[t],
+		})
+	}
+	return nil
+}
+
+// publishBatch collects the models of a call to PersistBatch by table.
+type publishBatch struct {
+	tables map[string][]interface{}
+}
+
+func (b *publishBatch) PersistModel(ctx context.Context, m interface{}) error {
+	value := reflect.ValueOf(m)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := b.PersistModel(ctx, value.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		q := orm.NewQuery(nil, m)
+		name := stripQuotes(q.TableModel().Table().SQLNameForSelects)
+		b.tables[name] = append(b.tables[name], m)
+		return nil
+	default:
+		return ErrMarshalUnsupportedType
+	}
+}
+
+type subscriber struct {
+	tables map[string]bool // tables the subscriber receives, all tables if empty
+	ch     chan *Batch
+}
+
+// subscriptions holds the subscribers of each job.
+type subscriptions struct {
+	mu    sync.Mutex
+	byJob map[string]map[*subscriber]struct{}
+}
+
+func (s *subscriptions) add(job string, sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.byJob[job] == nil {
+		s.byJob[job] = map[*subscriber]struct{}{}
+	}
+	s.byJob[job][sub] = struct{}{}
+}
+
+// remove removes a subscriber and closes its channel if it is still subscribed.
+func (s *subscriptions) remove(job string, sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.drop(job, sub)
+}
+
+func (s *subscriptions) drop(job string, sub *subscriber) {
+	if _, ok := s.byJob[job][sub]; !ok {
+		return
+	}
+	delete(s.byJob[job], sub)
+	close(sub.ch)
+	if len(s.byJob[job]) == 0 {
+		delete(s.byJob, job)
+	}
+}
+
+func (s *subscriptions) has(job string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.byJob[job]) > 0
+}
+
+// publish sends a batch to the job's subscribers without blocking, disconnecting those whose buffer is full.
+func (s *subscriptions) publish(b *Batch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.byJob[b.Job] {
+		if len(sub.tables) > 0 && !sub.tables[b.Table] {
+			continue
+		}
+		select {
+		case sub.ch <- b:
+		default:
+			log.Warnw("disconnecting slow subscriber", "job", b.Job, "buffer_size", cap(sub.ch))
+			s.drop(b.Job, sub)
+		}
+	}
+}
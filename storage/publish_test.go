@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/model"
+)
+
+type PublishedModel struct {
+	Height int64 `pg:",pk,notnull,use_zero"`
+}
+
+func (pm *PublishedModel) Persist(ctx context.Context, s model.StorageBatch, version model.Version) error {
+	return s.PersistModel(ctx, pm)
+}
+
+func TestPublishStorage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := NewPublishStorage(2)
+	strg := p.WithMetadata(Metadata{JobName: "watch"})
+
+	// models persisted without subscribers are discarded
+	require.NoError(t, strg.PersistBatch(ctx, &TestModel{Height: 1}))
+
+	all := p.Subscribe(ctx, "watch", nil)
+	filtered := p.Subscribe(ctx, "watch", []string{"published_models"})
+	other := p.Subscribe(ctx, "other", nil)
+
+	tm := &TestModel{Height: 2, Block: "b", Message: "m"}
+	pm := &PublishedModel{Height: 2}
+	require.NoError(t, strg.PersistBatch(ctx, model.PersistableList{tm, pm}))
+
+	b := <-all
+	assert.Equal(t, "watch", b.Job)
+	assert.Equal(t, "published_models", b.Table)
+	assert.Equal(t, []interface{}{pm}, b.Models)
+	b = <-all
+	assert.Equal(t, "test_models", b.Table)
+	assert.Equal(t, []interface{}{tm}, b.Models)
+
+	b = <-filtered
+	assert.Equal(t, "published_models", b.Table)
+	assert.Len(t, filtered, 0)
+	assert.Len(t, other, 0)
+
+	// a subscriber that falls more than the buffer size behind is disconnected
+	for i := 0; i < 3; i++ {
+		require.NoError(t, strg.PersistBatch(ctx, &TestModel{Height: int64(i)}))
+	}
+	assert.Len(t, all, 2)
+	<-all
+	<-all
+	_, open := <-all
+	assert.False(t, open)
+
+	// subscribers are removed when their context is done
+	sctx, scancel := context.WithCancel(ctx)
+	sub := p.Subscribe(sctx, "watch", nil)
+	scancel()
+	_, open = <-sub
+	assert.False(t, open)
+}
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/go-pg/pg/v10"
 	"github.com/go-pg/pg/v10/orm"
@@ -26,6 +27,7 @@ import (
 	"github.com/filecoin-project/lily/model/actors/verifreg"
 	"github.com/filecoin-project/lily/model/blocks"
 	"github.com/filecoin-project/lily/model/chain"
+	"github.com/filecoin-project/lily/model/consensusfaults"
 	"github.com/filecoin-project/lily/model/derived"
 	"github.com/filecoin-project/lily/model/messages"
 	"github.com/filecoin-project/lily/model/msapprovals"
@@ -44,6 +46,7 @@ var models = []interface{}{
 	(*miner.MinerSectorPost)(nil),
 	(*miner.MinerPreCommitInfo)(nil),
 	(*miner.MinerSectorEvent)(nil),
+	(*miner.MinerSectorLifecycle)(nil),
 	(*miner.MinerCurrentDeadlineInfo)(nil),
 	(*miner.MinerFeeDebt)(nil),
 	(*miner.MinerLockedFund)(nil),
@@ -57,27 +60,45 @@ var models = []interface{}{
 	(*messages.Receipt)(nil),
 	(*messages.MessageGasEconomy)(nil),
 	(*messages.ParsedMessage)(nil),
+	(*messages.ParsedReceipt)(nil),
 	(*messages.InternalMessage)(nil),
+	(*messages.InternalParsedReceipt)(nil),
+	(*messages.MultisigInnerMessage)(nil),
+	(*messages.MessageCall)(nil),
 
 	(*multisig.MultisigTransaction)(nil),
 
 	(*power.ChainPower)(nil),
 	(*power.PowerActorClaim)(nil),
+	(*power.PowerActorClaimConsensus)(nil),
 
 	(*reward.ChainReward)(nil),
 
 	(*common.Actor)(nil),
 	(*common.ActorState)(nil),
+	(*common.ActorLifecycleEvent)(nil),
+	(*common.ActorCode)(nil),
+	(*common.ActorMethod)(nil),
+	(*common.ActorExitCode)(nil),
 
 	(*init_.IdAddress)(nil),
 
 	(*derived.GasOutputs)(nil),
+	(*derived.BlockReward)(nil),
+	(*derived.MessageGasCharge)(nil),
+	(*derived.SenderNonces)(nil),
+	(*derived.NonceReplacement)(nil),
 
 	(*chain.ChainEconomics)(nil),
 	(*chain.ChainConsensus)(nil),
+	(*chain.EpochSummary)(nil),
+	(*chain.NetworkVersion)(nil),
+	(*chain.NetworkUpgrade)(nil),
 
 	(*msapprovals.MultisigApproval)(nil),
 
+	(*consensusfaults.ConsensusFault)(nil),
+
 	(*verifreg.VerifiedRegistryVerifier)(nil),
 	(*verifreg.VerifiedRegistryVerifiedClient)(nil),
 }
@@ -169,6 +190,10 @@ type Database struct {
 	Clock        clock.Clock
 	Upsert       bool
 	version      model.Version // schema version identified in the database
+
+	// EnrichTimestamps sets the time of rows persisted to tables that have a timestamp column
+	EnrichTimestamps bool
+	timestampTables  map[string]bool // tables with a timestamp column, loaded on connect
 }
 
 // Connect opens a connection to the database and checks that the schema is compatible with the version required
@@ -186,6 +211,15 @@ func (d *Database) Connect(ctx context.Context) error {
 		return err
 	}
 
+	if d.EnrichTimestamps {
+		tables, err := timestampTables(ctx, db, d.SchemaConfig().SchemaName)
+		if err != nil {
+			_ = db.Close() // nolint: errcheck
+			return err
+		}
+		d.timestampTables = tables
+	}
+
 	d.db = db
 	d.version = dbVersion
 
@@ -368,6 +402,9 @@ func (d *Database) PersistBatch(ctx context.Context, ps ...model.Persistable) er
 			tx:     tx,
 			upsert: d.Upsert,
 		}
+		if d.EnrichTimestamps {
+			txs.timestampTables = d.timestampTables
+		}
 
 		for _, p := range ps {
 			if err := p.Persist(ctx, txs, d.version); err != nil {
@@ -386,6 +423,10 @@ func (d *Database) ExecContext(c context.Context, query interface{}, params ...i
 type TxStorage struct {
 	tx     *pg.Tx
 	upsert bool
+
+	// timestampTables holds the tables whose rows have their time set when they are inserted, nil when timestamp
+	// enrichment is disabled
+	timestampTables map[string]bool
 }
 
 // PersistModel persists a single model
@@ -411,17 +452,50 @@ func (s *TxStorage) PersistModel(ctx context.Context, m interface{}) error {
 		}
 
 	}
+	models, err := s.timestampModels(ctx, m)
+	if err != nil {
+		return err
+	}
+	for _, tm := range models {
+		if err := s.insertModel(ctx, tm.model, tm.time); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertModel inserts m, setting the time column of its rows when t is not nil.
+func (s *TxStorage) insertModel(ctx context.Context, m interface{}, t *time.Time) error {
+	q := s.tx.ModelContext(ctx, m)
+	if t != nil {
+		q = q.Value(TimestampColumn, "?", *t)
+	}
+
+	if merger, ok := m.(model.Merger); ok {
+		conflict, set := merger.MergeConflict()
+		if _, err := q.OnConflict(conflict).
+			Set(set).
+			Insert(); err != nil {
+			return xerrors.Errorf("merging model: %w", err)
+		}
+		return nil
+	}
+
 	if s.upsert {
 		conflict, upsert := GenerateUpsertStrings(m)
-		if _, err := s.tx.ModelContext(ctx, m).
-			OnConflict(conflict).
+		if t != nil {
+			if upsert != "" {
+				upsert += ", "
+			}
+			upsert += "\"" + TimestampColumn + "\" = EXCLUDED." + TimestampColumn
+		}
+		if _, err := q.OnConflict(conflict).
 			Set(upsert).
 			Insert(); err != nil {
 			return xerrors.Errorf("upserting model: %w", err)
 		}
 	} else {
-		if _, err := s.tx.ModelContext(ctx, m).
-			OnConflict("do nothing").
+		if _, err := q.OnConflict("do nothing").
 			Insert(); err != nil {
 			return xerrors.Errorf("persisting model: %w", err)
 		}
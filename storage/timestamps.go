@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin"
+)
+
+// TimestampColumn is the name of the column holding the wallclock time of the epoch a row belongs to. It is added
+// to every table with a height column and filled in by storages that have timestamp enrichment enabled.
+const TimestampColumn = "time"
+
+type tipSetTimestampsKey struct{}
+
+// tipSetTimestamps maps the heights of tipsets to their minimum block timestamp in seconds since the Unix epoch.
+type tipSetTimestamps map[int64]uint64
+
+// WithTipSetTimestamps returns a context carrying the timestamps of the given tipsets. Storages with timestamp
+// enrichment enabled use them to set the time of the rows persisted with the returned context.
+func WithTipSetTimestamps(ctx context.Context, tss ...*types.TipSet) context.Context {
+	tt := tipSetTimestamps{}
+	if existing, ok := ctx.Value(tipSetTimestampsKey{}).(tipSetTimestamps); ok {
+		for h, t := range existing {
+			tt[h] = t
+		}
+	}
+	for _, ts := range tss {
+		if ts == nil {
+			continue
+		}
+		tt[int64(ts.Height())] = ts.MinTimestamp()
+	}
+	return context.WithValue(ctx, tipSetTimestampsKey{}, tt)
+}
+
+// epochTime returns the wallclock time of the epoch at height. Epochs without a known tipset, such as null rounds,
+// are timed relative to the closest known tipset below them, or above them if there is none below.
+func epochTime(ctx context.Context, height int64) (time.Time, bool) {
+	tt, ok := ctx.Value(tipSetTimestampsKey{}).(tipSetTimestamps)
+	if !ok || len(tt) == 0 {
+		return time.Time{}, false
+	}
+	if t, ok := tt[height]; ok {
+		return time.Unix(int64(t), 0).UTC(), true
+	}
+
+	heights := make([]int64, 0, len(tt))
+	for h := range tt {
+		heights = append(heights, h)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+
+	nearest := heights[0]
+	for _, h := range heights {
+		if h > height {
+			break
+		}
+		nearest = h
+	}
+	offset := (height - nearest) * int64(builtin.EpochDurationSeconds)
+	return time.Unix(int64(tt[nearest])+offset, 0).UTC(), true
+}
+
+// CreateTimestampIndexes creates an index on the time column of every table in the schema that has one. Indexes are
+// built concurrently so that tables can still be written to while they are created, which may take a long time for
+// large tables.
+func (d *Database) CreateTimestampIndexes(ctx context.Context) error {
+	db, err := connect(ctx, d.opt)
+	if err != nil {
+		return xerrors.Errorf("connect: %w", err)
+	}
+	defer db.Close() // nolint: errcheck
+
+	schemaName := d.SchemaConfig().SchemaName
+	tables, err := timestampTables(ctx, db, schemaName)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		log.Infof("creating timestamp index on %s", name)
+		// concurrent index builds cannot run in a transaction
+		if _, err := db.ExecContext(ctx, `CREATE INDEX CONCURRENTLY IF NOT EXISTS ? ON ?.? USING btree (? DESC)`,
+			pg.Ident(name+"_time_idx"), pg.Ident(schemaName), pg.Ident(name), pg.Ident(TimestampColumn)); err != nil {
+			return xerrors.Errorf("creating timestamp index on %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// timestampTables returns the names of the tables in schemaName that have a timestamp column.
+func timestampTables(ctx context.Context, db *pg.DB, schemaName string) (map[string]bool, error) {
+	var names []string
+	if _, err := db.QueryContext(ctx, &names, `SELECT table_name FROM information_schema.columns WHERE table_schema=? AND column_name=? AND data_type='timestamp with time zone'`, schemaName, TimestampColumn); err != nil {
+		return nil, xerrors.Errorf("querying timestamp columns: %w", err)
+	}
+
+	tables := make(map[string]bool, len(names))
+	for _, n := range names {
+		tables[n] = true
+	}
+	return tables, nil
+}
+
+// timestampedModel is a model to be inserted with its time column set to time, or left unset when time is nil.
+type timestampedModel struct {
+	model interface{}
+	time  *time.Time
+}
+
+// timestampModels splits m into models sharing a height so that each can be inserted with the time of that height.
+// m is returned as a single model without a time when timestamp enrichment is disabled or its table has no timestamp
+// column.
+func (s *TxStorage) timestampModels(ctx context.Context, m interface{}) ([]timestampedModel, error) {
+	untimed := []timestampedModel{{model: m}}
+	if s.timestampTables == nil {
+		return untimed, nil
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(m))
+
+	var elems []reflect.Value
+	isSlice := value.Kind() == reflect.Slice || value.Kind() == reflect.Array
+	if isSlice {
+		for i := 0; i < value.Len(); i++ {
+			elems = append(elems, value.Index(i))
+		}
+	} else {
+		elems = append(elems, value)
+	}
+
+	var tbl *orm.Table
+	var heights []int64
+	byHeight := map[int64][]reflect.Value{}
+	for _, elem := range elems {
+		// elements of versioned lists are held in interfaces
+		e := elem
+		for e.Kind() == reflect.Interface || e.Kind() == reflect.Ptr {
+			if e.IsNil() {
+				break
+			}
+			e = e.Elem()
+		}
+		if e.Kind() != reflect.Struct {
+			return untimed, nil
+		}
+		if tbl == nil {
+			tbl = orm.GetTable(e.Type())
+			if !s.timestampTables[stripQuotes(tbl.SQLNameForSelects)] {
+				return untimed, nil
+			}
+		}
+		fld, ok := tbl.FieldsMap["height"]
+		if !ok {
+			return untimed, nil
+		}
+
+		var h int64
+		hv := fld.Value(e)
+		switch hv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			h = hv.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			h = int64(hv.Uint())
+		default:
+			return untimed, nil
+		}
+
+		if _, ok := byHeight[h]; !ok {
+			heights = append(heights, h)
+		}
+		byHeight[h] = append(byHeight[h], elem)
+	}
+
+	if tbl == nil {
+		return untimed, nil
+	}
+
+	out := make([]timestampedModel, 0, len(heights))
+	for _, h := range heights {
+		tm := timestampedModel{model: m}
+		if t, ok := epochTime(ctx, h); ok {
+			tm.time = &t
+		}
+
+		if isSlice && len(heights) > 1 {
+			// keep the type of the list so that methods such as MergeConflict remain available, go-pg expects
+			// pointers to slices
+			typ := value.Type()
+			if typ.Kind() == reflect.Array {
+				typ = reflect.SliceOf(typ.Elem())
+			}
+			group := reflect.New(typ)
+			group.Elem().Set(reflect.Append(group.Elem(), byHeight[h]...))
+			tm.model = group.Interface()
+		}
+		out = append(out, tm)
+	}
+	return out, nil
+}
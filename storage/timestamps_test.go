@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/model"
+)
+
+func TestEpochTime(t *testing.T) {
+	_, ok := epochTime(context.Background(), 10)
+	require.False(t, ok, "no timestamps in context")
+
+	ctx := context.WithValue(context.Background(), tipSetTimestampsKey{}, tipSetTimestamps{
+		10: 1000,
+		13: 1090,
+	})
+
+	testCases := []struct {
+		height int64
+		want   int64
+	}{
+		{height: 10, want: 1000},
+		{height: 13, want: 1090},
+		{height: 11, want: 1030}, // null round after 10
+		{height: 12, want: 1060}, // null round after 10
+		{height: 15, want: 1150}, // after the last known tipset
+		{height: 8, want: 940},   // before the first known tipset
+	}
+
+	for _, tc := range testCases {
+		got, ok := epochTime(ctx, tc.height)
+		require.True(t, ok)
+		assert.Equal(t, time.Unix(tc.want, 0).UTC(), got, "height %d", tc.height)
+		assert.Equal(t, time.UTC, got.Location())
+	}
+}
+
+type timedModel struct {
+	//lint:ignore U1000 tableName is a convention used by go-pg
+	tableName struct{} `pg:"timed_models"`
+	Height    int64    `pg:",pk,notnull,use_zero"`
+	Value     string
+}
+
+type timedModelList []*timedModel
+
+func (l timedModelList) MergeConflict() (string, string) {
+	return "(height) DO UPDATE", "value = EXCLUDED.value"
+}
+
+func TestTimestampModels(t *testing.T) {
+	ctx := context.WithValue(context.Background(), tipSetTimestampsKey{}, tipSetTimestamps{
+		10: 1000,
+	})
+
+	list := &timedModelList{
+		{Height: 10, Value: "a"},
+		{Height: 11, Value: "b"},
+		{Height: 10, Value: "c"},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		s := &TxStorage{}
+		models, err := s.timestampModels(ctx, list)
+		require.NoError(t, err)
+		require.Len(t, models, 1)
+		assert.Nil(t, models[0].time)
+		assert.Equal(t, list, models[0].model)
+	})
+
+	t.Run("table without timestamps", func(t *testing.T) {
+		s := &TxStorage{timestampTables: map[string]bool{"other": true}}
+		models, err := s.timestampModels(ctx, list)
+		require.NoError(t, err)
+		require.Len(t, models, 1)
+		assert.Nil(t, models[0].time)
+	})
+
+	t.Run("grouped by height", func(t *testing.T) {
+		s := &TxStorage{timestampTables: map[string]bool{"timed_models": true}}
+		models, err := s.timestampModels(ctx, list)
+		require.NoError(t, err)
+		require.Len(t, models, 2)
+
+		require.NotNil(t, models[0].time)
+		assert.Equal(t, time.Unix(1000, 0).UTC(), *models[0].time)
+		first, ok := models[0].model.(*timedModelList)
+		require.True(t, ok, "list type is kept")
+		require.Len(t, *first, 2)
+		assert.Equal(t, "a", (*first)[0].Value)
+		assert.Equal(t, "c", (*first)[1].Value)
+
+		require.NotNil(t, models[1].time)
+		assert.Equal(t, time.Unix(1030, 0).UTC(), *models[1].time)
+		_, ok = models[1].model.(model.Merger)
+		assert.True(t, ok, "merge conflicts are kept")
+	})
+}
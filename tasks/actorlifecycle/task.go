@@ -0,0 +1,161 @@
+// Package actorlifecycle provides a task for recording the creation, deletion and upgrade of actors
+package actorlifecycle
+
+import (
+	"context"
+	"sort"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/lotus/chain/types"
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/label"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin"
+	"github.com/filecoin-project/lily/chain/actors/builtin/account"
+	init_ "github.com/filecoin-project/lily/chain/actors/builtin/init"
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/model"
+	commonmodel "github.com/filecoin-project/lily/model/actors/common"
+	visormodel "github.com/filecoin-project/lily/model/visor"
+)
+
+type Task struct {
+	node lens.API
+}
+
+func NewTask(node lens.API) *Task {
+	return &Task{
+		node: node,
+	}
+}
+
+// ProcessActors records an event for each actor that was created, deleted or had its code changed between the parent
+// states of pts and ts.
+func (p *Task) ProcessActors(ctx context.Context, ts *types.TipSet, pts *types.TipSet, actors map[string]lens.ActorStateChange, emsgs []*lens.ExecutedMessage) (model.Persistable, *visormodel.ProcessingReport, error) {
+	ctx, span := global.Tracer("").Start(ctx, "ProcessActorLifecycle")
+	if span.IsRecording() {
+		span.SetAttributes(label.String("tipset", ts.String()), label.Int64("height", int64(ts.Height())))
+	}
+	defer span.End()
+
+	report := &visormodel.ProcessingReport{
+		Height:    int64(pts.Height()),
+		StateRoot: pts.ParentState().String(),
+	}
+
+	// visit actors in a stable order so events are emitted consistently
+	ids := make([]string, 0, len(actors))
+	for id := range actors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var created map[address.Address]address.Address
+	out := commonmodel.ActorLifecycleEventList{}
+	for _, id := range ids {
+		select {
+		case <-ctx.Done():
+			return nil, nil, xerrors.Errorf("context done: %w", ctx.Err())
+		default:
+		}
+
+		ch := actors[id]
+		ev := &commonmodel.ActorLifecycleEvent{
+			Height:    int64(ts.Height()),
+			ID:        id,
+			StateRoot: ts.ParentState().String(),
+			Code:      builtin.ActorNameByCode(ch.Actor.Code),
+		}
+
+		switch ch.ChangeType {
+		case lens.ChangeTypeAdd:
+			ev.Event = commonmodel.ActorCreated
+		case lens.ChangeTypeRemove:
+			ev.Event = commonmodel.ActorDeleted
+		case lens.ChangeTypeModify:
+			if !ch.PrevCode.Defined() || ch.PrevCode.Equals(ch.Actor.Code) {
+				continue
+			}
+			ev.Event = commonmodel.ActorUpgraded
+			ev.PrevCode = builtin.ActorNameByCode(ch.PrevCode)
+		default:
+			continue
+		}
+
+		idAddr, err := address.NewFromString(id)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("parse actor address: %w", err)
+		}
+
+		// robust addresses of new actors are recorded by the init actor in the same state transition
+		if ch.ChangeType == lens.ChangeTypeAdd && created == nil {
+			created, err = p.createdAddresses(ctx, pts, actors)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		addr, err := p.robustAddress(idAddr, ch.Actor, created)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("resolve address of actor %s: %w", id, err)
+		}
+		ev.Address = addr.String()
+
+		out = append(out, ev)
+	}
+
+	return out, report, nil
+}
+
+// createdAddresses returns the robust addresses the init actor assigned to new ID addresses, keyed by ID address.
+func (p *Task) createdAddresses(ctx context.Context, pts *types.TipSet, actors map[string]lens.ActorStateChange) (map[address.Address]address.Address, error) {
+	out := map[address.Address]address.Address{}
+
+	ch, ok := actors[builtin0.InitActorAddr.String()]
+	if !ok {
+		// the address map of the init actor did not change
+		return out, nil
+	}
+
+	prevActor, err := p.node.StateGetActor(ctx, builtin0.InitActorAddr, pts.Key())
+	if err != nil {
+		return nil, xerrors.Errorf("loading previous init actor: %w", err)
+	}
+	prevState, err := init_.Load(p.node.Store(), prevActor)
+	if err != nil {
+		return nil, xerrors.Errorf("loading previous init actor state: %w", err)
+	}
+	curState, err := init_.Load(p.node.Store(), &ch.Actor)
+	if err != nil {
+		return nil, xerrors.Errorf("loading current init actor state: %w", err)
+	}
+
+	changes, err := init_.DiffAddressMap(ctx, p.node.Store(), prevState, curState)
+	if err != nil {
+		return nil, xerrors.Errorf("diffing init actor state: %w", err)
+	}
+	for _, pair := range changes.Added {
+		out[pair.ID] = pair.PK
+	}
+	return out, nil
+}
+
+// robustAddress returns the robust address of the actor with the ID address id, falling back to the ID address when
+// the actor does not have one.
+func (p *Task) robustAddress(id address.Address, act types.Actor, created map[address.Address]address.Address) (address.Address, error) {
+	if addr, ok := created[id]; ok {
+		return addr, nil
+	}
+
+	if builtin.IsAccountActor(act.Code) {
+		st, err := account.Load(p.node.Store(), &act)
+		if err != nil {
+			return address.Undef, xerrors.Errorf("loading account actor state: %w", err)
+		}
+		return st.PubkeyAddress()
+	}
+
+	return id, nil
+}
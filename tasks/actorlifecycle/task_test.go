@@ -0,0 +1,75 @@
+package actorlifecycle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/lotus/chain/types"
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
+	tutils "github.com/filecoin-project/specs-actors/support/testing"
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin"
+	"github.com/filecoin-project/lily/lens"
+	commonmodel "github.com/filecoin-project/lily/model/actors/common"
+	"github.com/filecoin-project/lily/testutil"
+)
+
+func TestProcessActors(t *testing.T) {
+	pts := testutil.FakeTipset(t)
+	ts, err := types.NewTipSet([]*types.BlockHeader{testutil.FakeBlockHeader(t, 2, testutil.RandomCid())})
+	require.NoError(t, err)
+
+	upgraded := tutils.NewIDAddr(t, 1000).String()
+	modified := tutils.NewIDAddr(t, 1001).String()
+	deleted := tutils.NewIDAddr(t, 1002).String()
+
+	actors := map[string]lens.ActorStateChange{
+		// upgraded by a network migration
+		upgraded: {
+			Actor:      types.Actor{Code: builtin2.StorageMinerActorCodeID},
+			ChangeType: lens.ChangeTypeModify,
+			PrevCode:   builtin0.StorageMinerActorCodeID,
+		},
+		// ordinary state change
+		modified: {
+			Actor:      types.Actor{Code: builtin2.StorageMinerActorCodeID},
+			ChangeType: lens.ChangeTypeModify,
+			PrevCode:   builtin2.StorageMinerActorCodeID,
+		},
+		deleted: {
+			Actor:      types.Actor{Code: builtin2.PaymentChannelActorCodeID},
+			ChangeType: lens.ChangeTypeRemove,
+		},
+	}
+
+	// the node is not needed since no actors were created and none are accounts
+	data, report, err := NewTask(nil).ProcessActors(context.Background(), ts, pts, actors, nil)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+
+	events, ok := data.(commonmodel.ActorLifecycleEventList)
+	require.True(t, ok)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, &commonmodel.ActorLifecycleEvent{
+		Height:    2,
+		ID:        upgraded,
+		StateRoot: ts.ParentState().String(),
+		Event:     commonmodel.ActorUpgraded,
+		Address:   upgraded,
+		Code:      builtin.ActorNameByCode(builtin2.StorageMinerActorCodeID),
+		PrevCode:  builtin.ActorNameByCode(builtin0.StorageMinerActorCodeID),
+	}, events[0])
+
+	assert.Equal(t, &commonmodel.ActorLifecycleEvent{
+		Height:    2,
+		ID:        deleted,
+		StateRoot: ts.ParentState().String(),
+		Event:     commonmodel.ActorDeleted,
+		Address:   deleted,
+		Code:      builtin.ActorNameByCode(builtin2.PaymentChannelActorCodeID),
+	}, events[1])
+}
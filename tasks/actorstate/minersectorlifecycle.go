@@ -0,0 +1,104 @@
+package actorstate
+
+import (
+	"context"
+
+	"github.com/ipfs/go-cid"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/label"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/metrics"
+	"github.com/filecoin-project/lily/model"
+	minermodel "github.com/filecoin-project/lily/model/actors/miner"
+)
+
+// MinerSectorLifecycleExtractor maintains sector lifecycles from the sector events of miner actors. It is only used when
+// the miner actor state task is not running, otherwise lifecycles are derived from the output of that task by
+// MinerSectorLifecyclesFromResults to avoid extracting the sector changes of each miner twice.
+type MinerSectorLifecycleExtractor struct{}
+
+func (MinerSectorLifecycleExtractor) Extract(ctx context.Context, a ActorInfo, emsgs []*lens.ExecutedMessage, node ActorStateAPI) (model.Persistable, error) {
+	ctx, span := global.Tracer("").Start(ctx, "MinerSectorLifecycleExtractor")
+	if span.IsRecording() {
+		span.SetAttributes(label.String("actor", a.Address.String()))
+	}
+	defer span.End()
+
+	stop := metrics.Timer(ctx, metrics.StateExtractionDuration)
+	defer stop()
+
+	ec, err := NewMinerStateExtractionContext(ctx, a, node)
+	if err != nil {
+		return nil, xerrors.Errorf("creating miner state extraction context: %w", err)
+	}
+
+	_, _, _, sectorEventsModel, err := ExtractMinerSectorData(ctx, ec, a, node)
+	if err != nil {
+		return nil, xerrors.Errorf("extracting miner sector changes: %w", err)
+	}
+
+	return ExtractMinerSectorLifecycles(sectorEventsModel), nil
+}
+
+// MinerSectorLifecyclesFromResults derives sector lifecycles from the data produced by the miner actor state task.
+func MinerSectorLifecyclesFromResults(data model.Persistable) minermodel.MinerSectorLifecycleList {
+	var events minermodel.MinerSectorEventList
+	switch d := data.(type) {
+	case model.PersistableList:
+		for _, p := range d {
+			if res, ok := p.(*minermodel.MinerTaskResult); ok {
+				events = append(events, res.SectorEventsModel...)
+			}
+		}
+	case *minermodel.MinerTaskResult:
+		events = d.SectorEventsModel
+	}
+	return ExtractMinerSectorLifecycles(events)
+}
+
+// ExtractMinerSectorLifecycles collapses the sector events of a single state change into one lifecycle update per sector.
+func ExtractMinerSectorLifecycles(events minermodel.MinerSectorEventList) minermodel.MinerSectorLifecycleList {
+	type sectorKey struct {
+		miner  string
+		sector uint64
+	}
+
+	out := make(minermodel.MinerSectorLifecycleList, 0, len(events))
+	index := make(map[sectorKey]*minermodel.MinerSectorLifecycle, len(events))
+	for _, ev := range events {
+		key := sectorKey{miner: ev.MinerID, sector: ev.SectorID}
+		lc, ok := index[key]
+		if !ok {
+			lc = &minermodel.MinerSectorLifecycle{
+				MinerID:  ev.MinerID,
+				SectorID: ev.SectorID,
+			}
+			index[key] = lc
+			out = append(out, lc)
+		}
+		lc.Apply(ev.Event, ev.Height)
+	}
+	return out
+}
+
+// A MinerSectorLifecycleExtractorMap extracts sector lifecycles from miner actors.
+type MinerSectorLifecycleExtractorMap struct{}
+
+func (MinerSectorLifecycleExtractorMap) Allow(code cid.Cid) bool {
+	for _, c := range miner.AllCodes() {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (m MinerSectorLifecycleExtractorMap) GetExtractor(code cid.Cid) (ActorStateExtractor, bool) {
+	if !m.Allow(code) {
+		return nil, false
+	}
+	return MinerSectorLifecycleExtractor{}, true
+}
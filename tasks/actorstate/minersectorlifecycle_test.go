@@ -0,0 +1,67 @@
+package actorstate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/model"
+	minermodel "github.com/filecoin-project/lily/model/actors/miner"
+	"github.com/filecoin-project/lily/tasks/actorstate"
+)
+
+func TestExtractMinerSectorLifecycles(t *testing.T) {
+	t.Run("collapses events for the same sector", func(t *testing.T) {
+		events := minermodel.MinerSectorEventList{
+			{Height: 100, MinerID: "f01000", SectorID: 1, Event: minermodel.PreCommitAdded},
+			{Height: 100, MinerID: "f01000", SectorID: 1, Event: minermodel.SectorAdded},
+			{Height: 100, MinerID: "f01000", SectorID: 2, Event: minermodel.SectorFaulted},
+			{Height: 100, MinerID: "f01001", SectorID: 1, Event: minermodel.SectorTerminated},
+		}
+
+		lcs := actorstate.ExtractMinerSectorLifecycles(events)
+		require.Len(t, lcs, 3)
+
+		assert.Equal(t, "f01000", lcs[0].MinerID)
+		assert.EqualValues(t, 1, lcs[0].SectorID)
+		require.NotNil(t, lcs[0].PreCommitEpoch)
+		require.NotNil(t, lcs[0].ProveCommitEpoch)
+		require.NotNil(t, lcs[0].SealingDuration)
+		assert.EqualValues(t, 0, *lcs[0].SealingDuration)
+		assert.Nil(t, lcs[0].ActiveDuration)
+		assert.Equal(t, minermodel.SectorAdded, lcs[0].LastEvent)
+
+		assert.EqualValues(t, 2, lcs[1].SectorID)
+		require.NotNil(t, lcs[1].FirstFaultEpoch)
+		assert.EqualValues(t, 100, *lcs[1].FirstFaultEpoch)
+		assert.Nil(t, lcs[1].PreCommitEpoch)
+
+		assert.Equal(t, "f01001", lcs[2].MinerID)
+		require.NotNil(t, lcs[2].TerminationEpoch)
+		assert.EqualValues(t, 100, lcs[2].LastEventHeight)
+	})
+
+	t.Run("no events", func(t *testing.T) {
+		lcs := actorstate.ExtractMinerSectorLifecycles(minermodel.MinerSectorEventList{})
+		assert.Len(t, lcs, 0)
+	})
+
+	t.Run("derived from miner task results", func(t *testing.T) {
+		data := model.PersistableList{
+			&minermodel.MinerTaskResult{SectorEventsModel: minermodel.MinerSectorEventList{
+				{Height: 100, MinerID: "f01000", SectorID: 1, Event: minermodel.PreCommitAdded},
+			}},
+			&minermodel.MinerTaskResult{SectorEventsModel: minermodel.MinerSectorEventList{
+				{Height: 100, MinerID: "f01001", SectorID: 1, Event: minermodel.SectorAdded},
+			}},
+		}
+
+		lcs := actorstate.MinerSectorLifecyclesFromResults(data)
+		require.Len(t, lcs, 2)
+		assert.Equal(t, "f01000", lcs[0].MinerID)
+		assert.Equal(t, minermodel.PreCommitAdded, lcs[0].LastEvent)
+		assert.Equal(t, "f01001", lcs[1].MinerID)
+		assert.Equal(t, minermodel.SectorAdded, lcs[1].LastEvent)
+	})
+}
@@ -97,9 +97,15 @@ func (StoragePowerExtractor) Extract(ctx context.Context, a ActorInfo, emsgs []*
 	if err != nil {
 		return nil, err
 	}
+
+	claimConsensusModel, err := ExtractClaimConsensus(ec, claimedPowerModel)
+	if err != nil {
+		return nil, err
+	}
 	return &powermodel.PowerTaskResult{
-		ChainPowerModel: chainPowerModel,
-		ClaimStateModel: claimedPowerModel,
+		ChainPowerModel:     chainPowerModel,
+		ClaimStateModel:     claimedPowerModel,
+		ClaimConsensusModel: claimConsensusModel,
 	}, nil
 }
 
@@ -183,3 +189,67 @@ func ExtractClaimedPower(ctx context.Context, ec *PowerStateExtractionContext) (
 	}
 	return claimModel, nil
 }
+
+// ExtractClaimConsensus records whether the miner of each extracted claim meets the consensus minimum power requirement.
+// When the consensus minimum may have changed since the previous state the eligibility of every claim is recorded,
+// since it can change for miners whose claim did not.
+func ExtractClaimConsensus(ec *PowerStateExtractionContext, claims powermodel.PowerActorClaimList) (powermodel.PowerActorClaimConsensusList, error) {
+	changed, err := consensusMinimumChanged(ec)
+	if err != nil {
+		return nil, err
+	}
+
+	var miners []address.Address
+	if changed {
+		if err := ec.CurrState.ForEachClaim(func(miner address.Address, _ power.Claim) error {
+			miners = append(miners, miner)
+			return nil
+		}); err != nil {
+			return nil, xerrors.Errorf("iterating claims: %w", err)
+		}
+	} else {
+		for _, claim := range claims {
+			miner, err := address.NewFromString(claim.MinerID)
+			if err != nil {
+				return nil, xerrors.Errorf("parsing claim miner address: %w", err)
+			}
+			miners = append(miners, miner)
+		}
+	}
+
+	out := make(powermodel.PowerActorClaimConsensusList, 0, len(miners))
+	for _, miner := range miners {
+		meets, err := ec.CurrState.MinerNominalPowerMeetsConsensusMinimum(miner)
+		if err != nil {
+			return nil, xerrors.Errorf("checking consensus minimum for miner %s: %w", miner, err)
+		}
+		out = append(out, &powermodel.PowerActorClaimConsensus{
+			Height:                int64(ec.CurrTs.Height()),
+			MinerID:               miner.String(),
+			StateRoot:             ec.CurrTs.ParentState().String(),
+			MeetsConsensusMinimum: meets,
+		})
+	}
+	return out, nil
+}
+
+// consensusMinimumChanged reports whether the consensus minimum may differ between the previous and current state.
+// The minimum depends on the version of the power actor, which sets the minimum power, and on the number of miners
+// above the minimum power, below a threshold of which smaller miners remain eligible.
+func consensusMinimumChanged(ec *PowerStateExtractionContext) (bool, error) {
+	if !ec.HasPreviousState() {
+		return true, nil
+	}
+	if !ec.PrevState.Code().Equals(ec.CurrState.Code()) {
+		return true, nil
+	}
+	prevParticipating, _, err := ec.PrevState.MinerCounts()
+	if err != nil {
+		return false, xerrors.Errorf("previous miner counts: %w", err)
+	}
+	currParticipating, _, err := ec.CurrState.MinerCounts()
+	if err != nil {
+		return false, xerrors.Errorf("current miner counts: %w", err)
+	}
+	return prevParticipating != currParticipating, nil
+}
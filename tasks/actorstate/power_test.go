@@ -134,6 +134,11 @@ func TestPowerExtractV0(t *testing.T) {
 		assert.Len(t, cp.ClaimStateModel, 1)
 		assert.EqualValues(t, newClaim.QualityAdjPower.String(), cp.ClaimStateModel[0].QualityAdjPower)
 		assert.EqualValues(t, newClaim.RawBytePower.String(), cp.ClaimStateModel[0].RawBytePower)
+
+		// other miners are above the minimum power so this small claim is not eligible
+		assert.Len(t, cp.ClaimConsensusModel, 1)
+		assert.EqualValues(t, minerAddr.String(), cp.ClaimConsensusModel[0].MinerID)
+		assert.False(t, cp.ClaimConsensusModel[0].MeetsConsensusMinimum)
 	})
 }
 
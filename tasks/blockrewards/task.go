@@ -0,0 +1,113 @@
+// Package blockrewards provides a task for attributing block rewards to the blocks that earned them
+package blockrewards
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/lotus/chain/types"
+	reward0 "github.com/filecoin-project/specs-actors/actors/builtin/reward"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/label"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors/adt"
+	"github.com/filecoin-project/lily/chain/actors/builtin/reward"
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/model"
+	"github.com/filecoin-project/lily/model/derived"
+	visormodel "github.com/filecoin-project/lily/model/visor"
+	"github.com/filecoin-project/lily/tasks/messages"
+)
+
+type Task struct{}
+
+func NewTask() *Task {
+	return &Task{}
+}
+
+func (p *Task) ProcessMessageExecutions(ctx context.Context, store adt.Store, ts *types.TipSet, pts *types.TipSet, mex []*lens.MessageExecution) (model.Persistable, *visormodel.ProcessingReport, error) {
+	ctx, span := global.Tracer("").Start(ctx, "ProcessBlockRewards")
+	if span.IsRecording() {
+		span.SetAttributes(label.String("tipset", ts.String()), label.Int64("height", int64(ts.Height())))
+	}
+	defer span.End()
+
+	report := &visormodel.ProcessingReport{
+		Height:    int64(pts.Height()),
+		StateRoot: pts.ParentState().String(),
+	}
+
+	// The reward actor is sent one AwardBlockReward message for each block in the tipset whose messages were executed,
+	// in the same order as the blocks appear in the tipset.
+	minerBlocks := make(map[string][]*types.BlockHeader, len(pts.Blocks()))
+	for _, bh := range pts.Blocks() {
+		minerBlocks[bh.Miner.String()] = append(minerBlocks[bh.Miner.String()], bh)
+	}
+
+	var (
+		results        = make(derived.BlockRewardList, 0, len(pts.Blocks()))
+		errorsDetected = make([]*messages.MessageError, 0)
+	)
+
+	for _, m := range mex {
+		select {
+		case <-ctx.Done():
+			return nil, nil, xerrors.Errorf("context done: %w", ctx.Err())
+		default:
+		}
+
+		if !m.Implicit || m.Message.To != reward.Address || m.Message.Method != reward.Methods.AwardBlockReward {
+			continue
+		}
+
+		// this type is the same between v0 and v5
+		var params reward0.AwardBlockRewardParams
+		if err := params.UnmarshalCBOR(bytes.NewReader(m.Message.Params)); err != nil {
+			errorsDetected = append(errorsDetected, &messages.MessageError{
+				Cid:   m.Cid,
+				Error: xerrors.Errorf("failed to decode award block reward params: %w", err).Error(),
+			})
+			continue
+		}
+
+		blks := minerBlocks[params.Miner.String()]
+		if len(blks) == 0 {
+			errorsDetected = append(errorsDetected, &messages.MessageError{
+				Cid:   m.Cid,
+				Error: xerrors.Errorf("no block found in tipset for rewarded miner %s", params.Miner).Error(),
+			})
+			continue
+		}
+		blk := blks[0]
+		minerBlocks[params.Miner.String()] = blks[1:]
+
+		// Every successful value transfer made by the reward actor while awarding the block is part of the total reward,
+		// whether it was paid to the miner or burnt because the miner could not accept it.
+		total := big.Zero()
+		for _, sub := range m.Ret.ExecutionTrace.Subcalls {
+			if sub.Msg == nil || sub.MsgRct == nil || !sub.MsgRct.ExitCode.IsSuccess() {
+				continue
+			}
+			total = big.Add(total, sub.Msg.Value)
+		}
+
+		results = append(results, &derived.BlockReward{
+			Height:      int64(m.Height),
+			Block:       blk.Cid().String(),
+			StateRoot:   m.StateRoot.String(),
+			Miner:       params.Miner.String(),
+			WinCount:    params.WinCount,
+			GasReward:   params.GasReward.String(),
+			Penalty:     params.Penalty.String(),
+			Reward:      big.Sub(total, params.GasReward).String(),
+			TotalReward: total.String(),
+		})
+	}
+
+	if len(errorsDetected) != 0 {
+		report.ErrorsDetected = errorsDetected
+	}
+	return results, report, nil
+}
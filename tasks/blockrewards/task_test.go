@@ -0,0 +1,134 @@
+package blockrewards
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/vm"
+	reward0 "github.com/filecoin-project/specs-actors/actors/builtin/reward"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin"
+	"github.com/filecoin-project/lily/chain/actors/builtin/reward"
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/model/derived"
+	"github.com/filecoin-project/lily/testutil"
+)
+
+func TestBlockRewards(t *testing.T) {
+	minerA, _ := address.NewIDAddress(1000)
+	minerB, _ := address.NewIDAddress(1001)
+
+	stateRoot := testutil.RandomCid()
+	parents := []cid.Cid{testutil.RandomCid()}
+	block := func(miner address.Address, ticket byte) *types.BlockHeader {
+		bh := testutil.FakeBlockHeader(t, 10, stateRoot)
+		bh.Miner = miner
+		bh.Parents = parents
+		bh.Ticket = &types.Ticket{VRFProof: []byte{ticket}}
+		return bh
+	}
+	pts, err := types.NewTipSet([]*types.BlockHeader{block(minerA, 1), block(minerB, 2), block(minerA, 3)})
+	require.NoError(t, err)
+
+	award := func(miner address.Address, gasReward int64, subcalls ...types.ExecutionTrace) *lens.MessageExecution {
+		params := reward0.AwardBlockRewardParams{
+			Miner:     miner,
+			Penalty:   big.NewInt(1),
+			GasReward: big.NewInt(gasReward),
+			WinCount:  1,
+		}
+		buf := new(bytes.Buffer)
+		require.NoError(t, params.MarshalCBOR(buf))
+
+		msg := &types.Message{From: builtin.SystemActorAddr, To: reward.Address, Value: big.Zero(), Method: reward.Methods.AwardBlockReward, Params: buf.Bytes()}
+		return &lens.MessageExecution{
+			Cid:       msg.Cid(),
+			StateRoot: stateRoot,
+			Height:    11,
+			Message:   msg,
+			Implicit:  true,
+			Ret: &vm.ApplyRet{
+				ExecutionTrace: types.ExecutionTrace{
+					Msg:      msg,
+					MsgRct:   &types.MessageReceipt{ExitCode: exitcode.Ok},
+					Subcalls: subcalls,
+				},
+			},
+		}
+	}
+	pay := func(to address.Address, method abi.MethodNum, value int64, code exitcode.ExitCode) types.ExecutionTrace {
+		return types.ExecutionTrace{
+			Msg:    &types.Message{From: reward.Address, To: to, Value: big.NewInt(value), Method: method},
+			MsgRct: &types.MessageReceipt{ExitCode: code},
+		}
+	}
+
+	// Awards arrive in tipset block order so the two blocks mined by minerA are matched in order.
+	mex := []*lens.MessageExecution{
+		award(minerA, 20, pay(minerA, 5, 120, exitcode.Ok)),
+		award(minerB, 30, pay(minerB, 5, 130, exitcode.Ok)),
+		// a miner that cannot accept the reward has it burnt instead, which still counts towards the total
+		award(minerA, 40, pay(minerA, 5, 140, exitcode.ErrForbidden), pay(builtin.BurntFundsActorAddr, builtin.MethodSend, 140, exitcode.Ok)),
+	}
+
+	res, report, err := NewTask().ProcessMessageExecutions(context.Background(), nil, pts, pts, mex)
+	require.NoError(t, err)
+	require.Nil(t, report.ErrorsDetected)
+
+	rewards, ok := res.(derived.BlockRewardList)
+	require.True(t, ok)
+	require.Len(t, rewards, 3)
+
+	blks := pts.Blocks()
+	expected := []struct {
+		block     *types.BlockHeader
+		gasReward string
+		reward    string
+		total     string
+	}{
+		{block: blks[0], gasReward: "20", reward: "100", total: "120"},
+		{block: blks[1], gasReward: "30", reward: "100", total: "130"},
+		{block: blks[2], gasReward: "40", reward: "100", total: "140"},
+	}
+	for i, want := range expected {
+		assert.Equal(t, want.block.Cid().String(), rewards[i].Block)
+		assert.Equal(t, want.block.Miner.String(), rewards[i].Miner)
+		assert.Equal(t, want.gasReward, rewards[i].GasReward)
+		assert.Equal(t, want.reward, rewards[i].Reward)
+		assert.Equal(t, want.total, rewards[i].TotalReward)
+		assert.Equal(t, "1", rewards[i].Penalty)
+		assert.EqualValues(t, 1, rewards[i].WinCount)
+	}
+}
+
+func TestBlockRewardsUnknownMiner(t *testing.T) {
+	unknown, _ := address.NewIDAddress(2000)
+	params := reward0.AwardBlockRewardParams{Miner: unknown, Penalty: big.Zero(), GasReward: big.Zero(), WinCount: 1}
+	buf := new(bytes.Buffer)
+	require.NoError(t, params.MarshalCBOR(buf))
+
+	msg := &types.Message{From: builtin.SystemActorAddr, To: reward.Address, Value: big.Zero(), Method: reward.Methods.AwardBlockReward, Params: buf.Bytes()}
+	m := &lens.MessageExecution{
+		Cid:       msg.Cid(),
+		StateRoot: testutil.RandomCid(),
+		Message:   msg,
+		Implicit:  true,
+		Ret:       &vm.ApplyRet{},
+	}
+
+	pts := testutil.FakeTipset(t)
+	res, report, err := NewTask().ProcessMessageExecutions(context.Background(), nil, pts, pts, []*lens.MessageExecution{m})
+	require.NoError(t, err)
+	assert.Len(t, res, 0)
+	require.NotNil(t, report.ErrorsDetected)
+	assert.Len(t, report.ErrorsDetected, 1)
+}
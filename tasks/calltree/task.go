@@ -0,0 +1,102 @@
+// Package calltree provides a task for recording the tree of calls made while executing messages
+package calltree
+
+import (
+	"context"
+
+	"github.com/filecoin-project/lotus/chain/types"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/label"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors/adt"
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/model"
+	messagemodel "github.com/filecoin-project/lily/model/messages"
+	visormodel "github.com/filecoin-project/lily/model/visor"
+)
+
+type Task struct{}
+
+func NewTask() *Task {
+	return &Task{}
+}
+
+func (p *Task) ProcessMessageExecutions(ctx context.Context, store adt.Store, ts *types.TipSet, pts *types.TipSet, mex []*lens.MessageExecution) (model.Persistable, *visormodel.ProcessingReport, error) {
+	ctx, span := global.Tracer("").Start(ctx, "ProcessCallTree")
+	if span.IsRecording() {
+		span.SetAttributes(label.String("tipset", ts.String()), label.Int64("height", int64(ts.Height())))
+	}
+	defer span.End()
+
+	report := &visormodel.ProcessingReport{
+		Height:    int64(pts.Height()),
+		StateRoot: pts.ParentState().String(),
+	}
+
+	results := make(messagemodel.MessageCallList, 0, len(mex)) // we don't know the cap since mex is recursive in nature.
+	for _, m := range mex {
+		select {
+		case <-ctx.Done():
+			return nil, nil, xerrors.Errorf("context done: %w", ctx.Err())
+		default:
+		}
+
+		if m.Ret == nil {
+			continue
+		}
+
+		results = append(results, CallTree(m)...)
+	}
+
+	return results, report, nil
+}
+
+// CallTree flattens the execution trace of a message into a list of calls in depth first order.
+func CallTree(m *lens.MessageExecution) messagemodel.MessageCallList {
+	var out messagemodel.MessageCallList
+	var walk func(et *types.ExecutionTrace, parent *int64, depth int64, sibling int64)
+	walk = func(et *types.ExecutionTrace, parent *int64, depth int64, sibling int64) {
+		call := int64(len(out))
+		mc := &messagemodel.MessageCall{
+			Height:    int64(m.Height),
+			StateRoot: m.StateRoot.String(),
+			Message:   m.Cid.String(),
+			Call:      call,
+			Parent:    parent,
+			Depth:     depth,
+			Sibling:   sibling,
+		}
+		if et.Msg != nil {
+			mc.Cid = et.Msg.Cid().String()
+			mc.From = et.Msg.From.String()
+			mc.To = et.Msg.To.String()
+			mc.Value = et.Msg.Value.String()
+			mc.Method = uint64(et.Msg.Method)
+		}
+		if et.MsgRct != nil {
+			mc.ExitCode = int64(et.MsgRct.ExitCode)
+			mc.GasUsed = et.MsgRct.GasUsed
+			mc.ReturnSize = int64(len(et.MsgRct.Return))
+		}
+		out = append(out, mc)
+
+		for i := range et.Subcalls {
+			walk(&et.Subcalls[i], &call, depth+1, int64(i))
+		}
+	}
+	top := m.Ret.ExecutionTrace
+	// messages that failed the checks made before execution, such as a nonce mismatch or a gas limit below the
+	// on-chain cost, have an empty trace
+	if top.Msg == nil {
+		top.Msg = m.Message
+	}
+	if top.MsgRct == nil {
+		top.MsgRct = &m.Ret.MessageReceipt
+	}
+	walk(&top, nil, 0, 0)
+
+	// the top level call is the message itself, which is identified by the cid it was included on chain with
+	out[0].Cid = m.Cid.String()
+	return out
+}
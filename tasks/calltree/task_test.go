@@ -0,0 +1,109 @@
+package calltree
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/testutil"
+)
+
+func TestCallTree(t *testing.T) {
+	msg := func(from, to uint64, method abi.MethodNum) *types.Message {
+		f, _ := address.NewIDAddress(from)
+		tt, _ := address.NewIDAddress(to)
+		return &types.Message{From: f, To: tt, Value: big.Zero(), Method: method}
+	}
+
+	top := msg(100, 1000, 2)
+	m := &lens.MessageExecution{
+		Cid:       top.Cid(),
+		StateRoot: testutil.RandomCid(),
+		Height:    10,
+		Message:   top,
+		Ret: &vm.ApplyRet{
+			ExecutionTrace: types.ExecutionTrace{
+				Msg:    top,
+				MsgRct: &types.MessageReceipt{ExitCode: exitcode.Ok, Return: []byte{0x80}},
+				Subcalls: []types.ExecutionTrace{
+					{
+						Msg:    msg(1000, 4, 3),
+						MsgRct: &types.MessageReceipt{ExitCode: exitcode.Ok},
+						Subcalls: []types.ExecutionTrace{
+							{
+								Msg:    msg(4, 1001, 0),
+								MsgRct: &types.MessageReceipt{ExitCode: exitcode.ErrInsufficientFunds},
+							},
+						},
+					},
+					{
+						Msg:    msg(1000, 2, 4),
+						MsgRct: &types.MessageReceipt{ExitCode: exitcode.Ok, Return: []byte{0x81, 0x00}},
+					},
+				},
+			},
+		},
+	}
+
+	calls := CallTree(m)
+	require.Len(t, calls, 4)
+
+	assert.EqualValues(t, 0, calls[0].Call)
+	assert.Nil(t, calls[0].Parent)
+	assert.EqualValues(t, 0, calls[0].Depth)
+	assert.Equal(t, m.Cid.String(), calls[0].Cid)
+	assert.EqualValues(t, 1, calls[0].ReturnSize)
+
+	require.NotNil(t, calls[1].Parent)
+	assert.EqualValues(t, 0, *calls[1].Parent)
+	assert.EqualValues(t, 1, calls[1].Depth)
+	assert.EqualValues(t, 0, calls[1].Sibling)
+
+	require.NotNil(t, calls[2].Parent)
+	assert.EqualValues(t, 1, *calls[2].Parent)
+	assert.EqualValues(t, 2, calls[2].Depth)
+	assert.EqualValues(t, exitcode.ErrInsufficientFunds, calls[2].ExitCode)
+
+	require.NotNil(t, calls[3].Parent)
+	assert.EqualValues(t, 0, *calls[3].Parent)
+	assert.EqualValues(t, 1, calls[3].Depth)
+	assert.EqualValues(t, 1, calls[3].Sibling)
+	assert.EqualValues(t, 2, calls[3].ReturnSize)
+	for _, c := range calls {
+		assert.Equal(t, m.Cid.String(), c.Message)
+	}
+}
+
+func TestCallTreeEmptyTrace(t *testing.T) {
+	from, _ := address.NewIDAddress(100)
+	to, _ := address.NewIDAddress(1000)
+	msg := &types.Message{From: from, To: to, Value: big.NewInt(5), Method: 2}
+
+	// messages that fail before execution, such as with a nonce mismatch, have an empty trace
+	m := &lens.MessageExecution{
+		Cid:       msg.Cid(),
+		StateRoot: testutil.RandomCid(),
+		Height:    10,
+		Message:   msg,
+		Ret: &vm.ApplyRet{
+			MessageReceipt: types.MessageReceipt{ExitCode: exitcode.SysErrSenderStateInvalid},
+		},
+	}
+
+	calls := CallTree(m)
+	require.Len(t, calls, 1)
+	assert.Equal(t, m.Cid.String(), calls[0].Cid)
+	assert.Equal(t, from.String(), calls[0].From)
+	assert.Equal(t, to.String(), calls[0].To)
+	assert.Equal(t, "5", calls[0].Value)
+	assert.EqualValues(t, 2, calls[0].Method)
+	assert.EqualValues(t, exitcode.SysErrSenderStateInvalid, calls[0].ExitCode)
+}
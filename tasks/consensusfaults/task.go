@@ -0,0 +1,146 @@
+// Package consensusfaults provides a task for recording consensus faults reported to miner actors
+package consensusfaults
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/lotus/chain/types"
+	miner5 "github.com/filecoin-project/specs-actors/v5/actors/builtin/miner"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/label"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors/adt"
+	"github.com/filecoin-project/lily/chain/actors/builtin"
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/model"
+	"github.com/filecoin-project/lily/model/consensusfaults"
+	visormodel "github.com/filecoin-project/lily/model/visor"
+	"github.com/filecoin-project/lily/tasks/messages"
+)
+
+// ReportConsensusFaultMethodNum is the same for all versions of the miner actor.
+const ReportConsensusFaultMethodNum = 15
+
+type Task struct{}
+
+func NewTask() *Task {
+	return &Task{}
+}
+
+func (p *Task) ProcessMessageExecutions(ctx context.Context, store adt.Store, ts *types.TipSet, pts *types.TipSet, mex []*lens.MessageExecution) (model.Persistable, *visormodel.ProcessingReport, error) {
+	ctx, span := global.Tracer("").Start(ctx, "ProcessConsensusFaults")
+	if span.IsRecording() {
+		span.SetAttributes(label.String("tipset", ts.String()), label.Int64("height", int64(ts.Height())))
+	}
+	defer span.End()
+
+	report := &visormodel.ProcessingReport{
+		Height:    int64(pts.Height()),
+		StateRoot: pts.ParentState().String(),
+	}
+
+	var (
+		results        = make(consensusfaults.ConsensusFaultList, 0) // no initial size capacity since faults are rare
+		errorsDetected = make([]*messages.MessageError, 0)
+	)
+
+	for _, m := range mex {
+		select {
+		case <-ctx.Done():
+			return nil, nil, xerrors.Errorf("context done: %w", ctx.Err())
+		default:
+		}
+
+		// Only interested in successful fault reports sent to miner actors
+		if m.Implicit || !builtin.IsStorageMinerActor(m.ToActorCode) || m.Message.Method != ReportConsensusFaultMethodNum {
+			continue
+		}
+		if !m.Ret.ExitCode.IsSuccess() {
+			continue
+		}
+
+		fault, err := extractConsensusFault(m)
+		if err != nil {
+			errorsDetected = append(errorsDetected, &messages.MessageError{
+				Cid:   m.Cid,
+				Error: xerrors.Errorf("failed to extract consensus fault: %w", err).Error(),
+			})
+			continue
+		}
+		results = append(results, fault)
+	}
+
+	if len(errorsDetected) != 0 {
+		report.ErrorsDetected = errorsDetected
+	}
+	return results, report, nil
+}
+
+func extractConsensusFault(m *lens.MessageExecution) (*consensusfaults.ConsensusFault, error) {
+	// this type is the same between v0 and v5
+	var params miner5.ReportConsensusFaultParams
+	if err := params.UnmarshalCBOR(bytes.NewReader(m.Message.Params)); err != nil {
+		return nil, xerrors.Errorf("failed to decode message params: %w", err)
+	}
+
+	var blockA, blockB types.BlockHeader
+	if err := blockA.UnmarshalCBOR(bytes.NewReader(params.BlockHeader1)); err != nil {
+		return nil, xerrors.Errorf("failed to decode first block header: %w", err)
+	}
+	if err := blockB.UnmarshalCBOR(bytes.NewReader(params.BlockHeader2)); err != nil {
+		return nil, xerrors.Errorf("failed to decode second block header: %w", err)
+	}
+
+	fault := &consensusfaults.ConsensusFault{
+		Height:       int64(m.Height),
+		StateRoot:    m.StateRoot.String(),
+		Message:      m.Cid.String(),
+		Reporter:     m.Message.From.String(),
+		Miner:        blockA.Miner.String(),
+		FaultEpoch:   int64(blockA.Height),
+		BlockHeader1: blockA.Cid().String(),
+		BlockHeader2: blockB.Cid().String(),
+	}
+
+	// Classify the fault using the same order of checks as the VerifyConsensusFault syscall
+	switch {
+	case blockA.Height == blockB.Height:
+		fault.FaultType = consensusfaults.DoubleForkMining
+	case types.CidArrsEqual(blockA.Parents, blockB.Parents):
+		fault.FaultType = consensusfaults.TimeOffsetMining
+	case len(params.BlockHeaderExtra) > 0:
+		fault.FaultType = consensusfaults.ParentGrinding
+	default:
+		return nil, xerrors.Errorf("unable to classify fault for blocks %s and %s", fault.BlockHeader1, fault.BlockHeader2)
+	}
+
+	if len(params.BlockHeaderExtra) > 0 {
+		var blockC types.BlockHeader
+		if err := blockC.UnmarshalCBOR(bytes.NewReader(params.BlockHeaderExtra)); err != nil {
+			return nil, xerrors.Errorf("failed to decode extra block header: %w", err)
+		}
+		fault.BlockHeaderExtra = blockC.Cid().String()
+	}
+
+	// The miner burns the penalty and pays the reporter with plain value transfers sent during the same execution.
+	// The miner repays any outstanding fee debt in the same burn as the fault penalty so the two cannot be told
+	// apart from the trace: the recorded penalty is the total burnt while processing the report.
+	penalty, reward := big.Zero(), big.Zero()
+	for _, sub := range m.Ret.ExecutionTrace.Subcalls {
+		if sub.Msg == nil || sub.MsgRct == nil || sub.Msg.Method != builtin.MethodSend || !sub.MsgRct.ExitCode.IsSuccess() {
+			continue
+		}
+		if sub.Msg.To == builtin.BurntFundsActorAddr {
+			penalty = big.Add(penalty, sub.Msg.Value)
+		} else {
+			reward = big.Add(reward, sub.Msg.Value)
+		}
+	}
+	fault.Penalty = penalty.String()
+	fault.ReporterReward = reward.String()
+
+	return fault, nil
+}
@@ -0,0 +1,186 @@
+package consensusfaults
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/vm"
+	builtin5 "github.com/filecoin-project/specs-actors/v5/actors/builtin"
+	miner5 "github.com/filecoin-project/specs-actors/v5/actors/builtin/miner"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin"
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/model/consensusfaults"
+	"github.com/filecoin-project/lily/testutil"
+)
+
+func marshalHeader(t *testing.T, bh *types.BlockHeader) []byte {
+	t.Helper()
+	if bh == nil {
+		return nil
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, bh.MarshalCBOR(buf))
+	return buf.Bytes()
+}
+
+func faultReport(t *testing.T, blockA, blockB, blockC *types.BlockHeader, subcalls []types.ExecutionTrace) *lens.MessageExecution {
+	t.Helper()
+	params := miner5.ReportConsensusFaultParams{
+		BlockHeader1:     marshalHeader(t, blockA),
+		BlockHeader2:     marshalHeader(t, blockB),
+		BlockHeaderExtra: marshalHeader(t, blockC),
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, params.MarshalCBOR(buf))
+
+	reporter, _ := address.NewIDAddress(100)
+	miner, _ := address.NewIDAddress(1000)
+	msg := &types.Message{From: reporter, To: miner, Value: big.Zero(), Method: ReportConsensusFaultMethodNum, Params: buf.Bytes()}
+
+	return &lens.MessageExecution{
+		Cid:         msg.Cid(),
+		StateRoot:   testutil.RandomCid(),
+		Height:      20,
+		Message:     msg,
+		ToActorCode: builtin5.StorageMinerActorCodeID,
+		Ret: &vm.ApplyRet{
+			MessageReceipt: types.MessageReceipt{ExitCode: exitcode.Ok},
+			ExecutionTrace: types.ExecutionTrace{
+				Msg:      msg,
+				MsgRct:   &types.MessageReceipt{ExitCode: exitcode.Ok},
+				Subcalls: subcalls,
+			},
+		},
+	}
+}
+
+func TestFaultClassification(t *testing.T) {
+	parents := []cid.Cid{testutil.RandomCid()}
+	header := func(height int64, parents []cid.Cid) *types.BlockHeader {
+		bh := testutil.FakeBlockHeader(t, height, testutil.RandomCid())
+		bh.Parents = parents
+		return bh
+	}
+
+	testCases := []struct {
+		name      string
+		blockA    *types.BlockHeader
+		blockB    *types.BlockHeader
+		blockC    *types.BlockHeader
+		faultType string
+		wantErr   bool
+	}{
+		{
+			name:      "double fork mining",
+			blockA:    header(10, parents),
+			blockB:    header(10, []cid.Cid{testutil.RandomCid()}),
+			faultType: consensusfaults.DoubleForkMining,
+		},
+		{
+			name:      "time offset mining",
+			blockA:    header(10, parents),
+			blockB:    header(11, parents),
+			faultType: consensusfaults.TimeOffsetMining,
+		},
+		{
+			name:      "parent grinding",
+			blockA:    header(10, parents),
+			blockB:    header(11, []cid.Cid{testutil.RandomCid()}),
+			blockC:    header(10, []cid.Cid{testutil.RandomCid()}),
+			faultType: consensusfaults.ParentGrinding,
+		},
+		{
+			name:    "unclassified",
+			blockA:  header(10, parents),
+			blockB:  header(11, []cid.Cid{testutil.RandomCid()}),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := faultReport(t, tc.blockA, tc.blockB, tc.blockC, nil)
+			fault, err := extractConsensusFault(m)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.faultType, fault.FaultType)
+			assert.Equal(t, tc.blockA.Miner.String(), fault.Miner)
+			assert.EqualValues(t, tc.blockA.Height, fault.FaultEpoch)
+			assert.Equal(t, tc.blockA.Cid().String(), fault.BlockHeader1)
+			assert.Equal(t, tc.blockB.Cid().String(), fault.BlockHeader2)
+			if tc.blockC != nil {
+				assert.Equal(t, tc.blockC.Cid().String(), fault.BlockHeaderExtra)
+			} else {
+				assert.Empty(t, fault.BlockHeaderExtra)
+			}
+		})
+	}
+}
+
+func TestPenaltyAndReward(t *testing.T) {
+	miner, _ := address.NewIDAddress(1000)
+	reporter, _ := address.NewIDAddress(100)
+	send := func(to address.Address, value int64, code exitcode.ExitCode) types.ExecutionTrace {
+		return types.ExecutionTrace{
+			Msg:    &types.Message{From: miner, To: to, Value: big.NewInt(value), Method: builtin.MethodSend},
+			MsgRct: &types.MessageReceipt{ExitCode: code},
+		}
+	}
+
+	blockA := testutil.FakeBlockHeader(t, 10, testutil.RandomCid())
+	blockB := testutil.FakeBlockHeader(t, 10, testutil.RandomCid())
+	blockB.Parents = []cid.Cid{testutil.RandomCid()}
+
+	m := faultReport(t, blockA, blockB, nil, []types.ExecutionTrace{
+		send(reporter, 7, exitcode.Ok),
+		send(builtin.BurntFundsActorAddr, 50, exitcode.Ok),
+		// a failed send moves no funds
+		send(reporter, 1000, exitcode.ErrInsufficientFunds),
+		// fee debt repaid by the miner is burnt alongside the penalty
+		send(builtin.BurntFundsActorAddr, 3, exitcode.Ok),
+		{
+			// calls other than plain sends are not transfers to the reporter
+			Msg:    &types.Message{From: miner, To: builtin5.StoragePowerActorAddr, Value: big.NewInt(11), Method: builtin5.MethodsPower.UpdatePledgeTotal},
+			MsgRct: &types.MessageReceipt{ExitCode: exitcode.Ok},
+		},
+	})
+
+	fault, err := extractConsensusFault(m)
+	require.NoError(t, err)
+	assert.Equal(t, "53", fault.Penalty)
+	assert.Equal(t, "7", fault.ReporterReward)
+	assert.Equal(t, reporter.String(), fault.Reporter)
+	assert.Equal(t, m.Cid.String(), fault.Message)
+}
+
+func TestSkipsFailedReports(t *testing.T) {
+	blockA := testutil.FakeBlockHeader(t, 10, testutil.RandomCid())
+	blockB := testutil.FakeBlockHeader(t, 10, testutil.RandomCid())
+	blockB.Parents = []cid.Cid{testutil.RandomCid()}
+
+	ok := faultReport(t, blockA, blockB, nil, nil)
+	failed := faultReport(t, blockA, blockB, nil, nil)
+	failed.Ret.ExitCode = exitcode.ErrIllegalArgument
+
+	ts := testutil.FakeTipset(t)
+	res, report, err := NewTask().ProcessMessageExecutions(context.Background(), nil, ts, ts, []*lens.MessageExecution{ok, failed})
+	require.NoError(t, err)
+	require.Nil(t, report.ErrorsDetected)
+
+	faults, isList := res.(consensusfaults.ConsensusFaultList)
+	require.True(t, isList)
+	require.Len(t, faults, 1)
+	assert.Equal(t, consensusfaults.DoubleForkMining, faults[0].FaultType)
+}
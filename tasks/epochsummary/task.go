@@ -0,0 +1,107 @@
+// Package epochsummary provides a task for summarizing each epoch of the chain
+package epochsummary
+
+import (
+	"context"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/ipfs/go-cid"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/label"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/model"
+	"github.com/filecoin-project/lily/model/chain"
+	visormodel "github.com/filecoin-project/lily/model/visor"
+)
+
+type Task struct{}
+
+func NewTask() *Task {
+	return &Task{}
+}
+
+// ProcessMessages summarizes pts and the execution of its messages, which is recorded in ts. Any null rounds between
+// pts and ts are summarized as well.
+func (p *Task) ProcessMessages(ctx context.Context, ts *types.TipSet, pts *types.TipSet, emsgs []*lens.ExecutedMessage, blkMsgs []*lens.BlockMessages) (model.Persistable, *visormodel.ProcessingReport, error) {
+	ctx, span := global.Tracer("").Start(ctx, "ProcessEpochSummary")
+	if span.IsRecording() {
+		span.SetAttributes(label.String("tipset", ts.String()), label.Int64("height", int64(ts.Height())))
+	}
+	defer span.End()
+
+	report := &visormodel.ProcessingReport{
+		Height:    int64(pts.Height()),
+		StateRoot: pts.ParentState().String(),
+	}
+
+	summary := &chain.EpochSummary{
+		Height:       int64(pts.Height()),
+		StateRoot:    pts.ParentState().String(),
+		BlockCount:   int64(len(pts.Blocks())),
+		BaseFee:      pts.Blocks()[0].ParentBaseFee.String(),
+		MinTimestamp: pts.MinTimestamp(),
+	}
+	for _, bh := range pts.Blocks() {
+		if bh.Timestamp > summary.MaxTimestamp {
+			summary.MaxTimestamp = bh.Timestamp
+		}
+	}
+
+	seen := make(map[cid.Cid]bool)
+	for _, bm := range blkMsgs {
+		select {
+		case <-ctx.Done():
+			return nil, nil, xerrors.Errorf("context done: %w", ctx.Err())
+		default:
+		}
+
+		for _, msg := range bm.BlsMessages {
+			summary.TotalMessages++
+			if !seen[msg.Cid()] {
+				seen[msg.Cid()] = true
+				summary.UniqueMessages++
+				summary.GasLimit += msg.GasLimit
+			}
+		}
+		for _, msg := range bm.SecpMessages {
+			summary.TotalMessages++
+			if !seen[msg.Cid()] {
+				seen[msg.Cid()] = true
+				summary.UniqueMessages++
+				summary.GasLimit += msg.Message.GasLimit
+			}
+		}
+	}
+
+	// an executed message may be listed once for each block that included it so only count it once
+	executed := make(map[cid.Cid]bool, len(emsgs))
+	burnt, tip := big.Zero(), big.Zero()
+	for _, m := range emsgs {
+		if executed[m.Cid] {
+			continue
+		}
+		executed[m.Cid] = true
+		summary.GasUsed += m.Receipt.GasUsed
+		burnt = big.Sum(burnt, m.GasOutputs.BaseFeeBurn, m.GasOutputs.OverEstimationBurn)
+		tip = big.Add(tip, m.GasOutputs.MinerTip)
+	}
+	summary.TotalBurnt = burnt.String()
+	summary.TotalMinerTip = tip.String()
+
+	results := chain.EpochSummaryList{summary}
+	for epoch := pts.Height() + 1; epoch < ts.Height(); epoch++ {
+		results = append(results, &chain.EpochSummary{
+			Height:        int64(epoch),
+			StateRoot:     pts.ParentState().String(),
+			NullRound:     true,
+			BaseFee:       "0",
+			TotalBurnt:    "0",
+			TotalMinerTip: "0",
+		})
+	}
+
+	return results, report, nil
+}
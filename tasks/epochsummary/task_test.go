@@ -0,0 +1,104 @@
+package epochsummary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/vm"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/model/chain"
+	"github.com/filecoin-project/lily/testutil"
+)
+
+func TestEpochSummary(t *testing.T) {
+	stateRoot := testutil.RandomCid()
+	parents := []cid.Cid{testutil.RandomCid()}
+	block := func(height int64, ticket byte, timestamp uint64) *types.BlockHeader {
+		bh := testutil.FakeBlockHeader(t, height, stateRoot)
+		bh.Parents = parents
+		bh.Ticket = &types.Ticket{VRFProof: []byte{ticket}}
+		bh.ParentBaseFee = big.NewInt(100)
+		bh.Timestamp = timestamp
+		return bh
+	}
+	pts, err := types.NewTipSet([]*types.BlockHeader{block(10, 1, 30), block(10, 2, 32)})
+	require.NoError(t, err)
+	// epochs 11 and 12 are null rounds
+	ts, err := types.NewTipSet([]*types.BlockHeader{testutil.FakeBlockHeader(t, 13, testutil.RandomCid())})
+	require.NoError(t, err)
+
+	from, _ := address.NewIDAddress(100)
+	to, _ := address.NewIDAddress(1000)
+	bls := &types.Message{From: from, To: to, Nonce: 1, Value: big.Zero(), GasLimit: 1000}
+	secp := &types.SignedMessage{
+		Message:   types.Message{From: from, To: to, Nonce: 2, Value: big.Zero(), GasLimit: 2000},
+		Signature: crypto.Signature{Type: crypto.SigTypeSecp256k1, Data: []byte{1}},
+	}
+
+	// the bls message is included in both blocks
+	blkMsgs := []*lens.BlockMessages{
+		{Block: pts.Blocks()[0], BlsMessages: []*types.Message{bls}},
+		{Block: pts.Blocks()[1], BlsMessages: []*types.Message{bls}, SecpMessages: []*types.SignedMessage{secp}},
+	}
+
+	executed := func(c cid.Cid, gasUsed, burn, overBurn, tip int64) *lens.ExecutedMessage {
+		return &lens.ExecutedMessage{
+			Cid:     c,
+			Receipt: &types.MessageReceipt{GasUsed: gasUsed},
+			GasOutputs: vm.GasOutputs{
+				BaseFeeBurn:        big.NewInt(burn),
+				OverEstimationBurn: big.NewInt(overBurn),
+				MinerTip:           big.NewInt(tip),
+			},
+		}
+	}
+	emsgs := []*lens.ExecutedMessage{
+		executed(bls.Cid(), 600, 60, 5, 7),
+		executed(secp.Cid(), 1500, 150, 10, 11),
+		// a message listed for each block that included it is only counted once
+		executed(bls.Cid(), 600, 60, 5, 7),
+	}
+
+	res, report, err := NewTask().ProcessMessages(context.Background(), ts, pts, emsgs, blkMsgs)
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, report.Height)
+
+	summaries, ok := res.(chain.EpochSummaryList)
+	require.True(t, ok)
+	require.Len(t, summaries, 3)
+
+	summary := summaries[0]
+	assert.EqualValues(t, 10, summary.Height)
+	assert.Equal(t, stateRoot.String(), summary.StateRoot)
+	assert.False(t, summary.NullRound)
+	assert.EqualValues(t, 2, summary.BlockCount)
+	assert.EqualValues(t, 3, summary.TotalMessages)
+	assert.EqualValues(t, 2, summary.UniqueMessages)
+	assert.EqualValues(t, 3000, summary.GasLimit)
+	assert.EqualValues(t, 2100, summary.GasUsed)
+	assert.Equal(t, "100", summary.BaseFee)
+	assert.Equal(t, "225", summary.TotalBurnt)
+	assert.Equal(t, "18", summary.TotalMinerTip)
+	assert.EqualValues(t, 30, summary.MinTimestamp)
+	assert.EqualValues(t, 32, summary.MaxTimestamp)
+
+	for i, epoch := range []int64{11, 12} {
+		null := summaries[i+1]
+		assert.EqualValues(t, epoch, null.Height)
+		assert.Equal(t, stateRoot.String(), null.StateRoot)
+		assert.True(t, null.NullRound)
+		assert.Zero(t, null.BlockCount)
+		assert.Zero(t, null.TotalMessages)
+		assert.Equal(t, "0", null.BaseFee)
+		assert.Equal(t, "0", null.TotalBurnt)
+		assert.Equal(t, "0", null.TotalMinerTip)
+	}
+}
@@ -0,0 +1,170 @@
+// Package gastrace provides a task for recording the gas charged while executing messages
+package gastrace
+
+import (
+	"context"
+
+	"github.com/filecoin-project/lotus/chain/types"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/label"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors/adt"
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/model"
+	"github.com/filecoin-project/lily/model/derived"
+	visormodel "github.com/filecoin-project/lily/model/visor"
+)
+
+// Level controls how much detail about gas charges is recorded.
+type Level string
+
+const (
+	// LevelMessage sums the charges of all calls made by a message.
+	LevelMessage Level = "message"
+	// LevelCall sums the charges of each call made by a message separately.
+	LevelCall Level = "call"
+)
+
+// AllCalls is the call index of gas charges summed over all calls made by a message.
+const AllCalls = -1
+
+// ParseLevel returns the level named by s. An empty string selects LevelMessage.
+func ParseLevel(s string) (Level, error) {
+	switch Level(s) {
+	case "", LevelMessage:
+		return LevelMessage, nil
+	case LevelCall:
+		return LevelCall, nil
+	default:
+		return "", xerrors.Errorf("unknown gas trace level: %q", s)
+	}
+}
+
+type Task struct {
+	level Level
+}
+
+// ErrNoGasCharges is returned when none of the execution traces of the messages of a tipset carry gas charges. Messages
+// that fail the checks made before execution, such as a nonce mismatch or a gas limit below the on-chain cost, have
+// an empty trace but every other message is charged gas, so this means gas tracing was not enabled when the messages
+// were executed. Gas tracing is enabled when starting the daemon with the --gas-tracing option.
+var ErrNoGasCharges = xerrors.New("execution traces have no gas charges, gas tracing may not be enabled")
+
+// NewTask returns a task recording gas charges at the given level.
+func NewTask(level Level) *Task {
+	return &Task{
+		level: level,
+	}
+}
+
+func (p *Task) ProcessMessageExecutions(ctx context.Context, store adt.Store, ts *types.TipSet, pts *types.TipSet, mex []*lens.MessageExecution) (model.Persistable, *visormodel.ProcessingReport, error) {
+	ctx, span := global.Tracer("").Start(ctx, "ProcessGasTrace")
+	if span.IsRecording() {
+		span.SetAttributes(label.String("tipset", ts.String()), label.Int64("height", int64(ts.Height())), label.String("level", string(p.level)))
+	}
+	defer span.End()
+
+	report := &visormodel.ProcessingReport{
+		Height:    int64(pts.Height()),
+		StateRoot: pts.ParentState().String(),
+	}
+
+	results := make(derived.MessageGasChargeList, 0, len(mex))
+	executed := 0
+	for _, m := range mex {
+		select {
+		case <-ctx.Done():
+			return nil, nil, xerrors.Errorf("context done: %w", ctx.Err())
+		default:
+		}
+
+		if m.Ret == nil {
+			continue
+		}
+
+		charges := &chargeAggregator{
+			height:    int64(m.Height),
+			stateRoot: m.StateRoot.String(),
+			message:   m.Cid.String(),
+			index:     map[chargeKey]*derived.MessageGasCharge{},
+		}
+
+		executed++
+		// messages that failed before execution have no charges
+		charges.addTrace(&m.Ret.ExecutionTrace, p.level)
+		results = append(results, charges.out...)
+	}
+
+	if executed > 0 && len(results) == 0 {
+		return nil, nil, xerrors.Errorf("%d messages at height %d: %w", executed, pts.Height(), ErrNoGasCharges)
+	}
+
+	return results, report, nil
+}
+
+// walkExecutionTrace calls fn for et and each of its subcalls in depth first order.
+func walkExecutionTrace(et *types.ExecutionTrace, fn func(et *types.ExecutionTrace)) {
+	fn(et)
+	for i := range et.Subcalls {
+		walkExecutionTrace(&et.Subcalls[i], fn)
+	}
+}
+
+type chargeKey struct {
+	call int64
+	name string
+}
+
+// chargeAggregator sums the gas charges of a single message by call and name, keeping the order in which each
+// combination was first seen.
+type chargeAggregator struct {
+	height    int64
+	stateRoot string
+	message   string
+	index     map[chargeKey]*derived.MessageGasCharge
+	out       derived.MessageGasChargeList
+}
+
+// addTrace adds the gas charges of et and its subcalls, summed by call when level is LevelCall.
+func (a *chargeAggregator) addTrace(et *types.ExecutionTrace, level Level) {
+	var call int64
+	walkExecutionTrace(et, func(et *types.ExecutionTrace) {
+		idx := int64(AllCalls)
+		if level == LevelCall {
+			idx = call
+		}
+		for _, gc := range et.GasCharges {
+			a.add(idx, gc)
+		}
+		call++
+	})
+}
+
+func (a *chargeAggregator) add(call int64, gc *types.GasTrace) {
+	if gc == nil {
+		return
+	}
+
+	key := chargeKey{call: call, name: gc.Name}
+	c, ok := a.index[key]
+	if !ok {
+		c = &derived.MessageGasCharge{
+			Height:    a.height,
+			StateRoot: a.stateRoot,
+			Message:   a.message,
+			Call:      call,
+			Name:      gc.Name,
+		}
+		a.index[key] = c
+		a.out = append(a.out, c)
+	}
+
+	c.Count++
+	c.TotalGas += gc.TotalGas
+	c.ComputeGas += gc.ComputeGas
+	c.StorageGas += gc.StorageGas
+	c.TotalVirtualGas += gc.TotalVirtualGas
+	c.VirtualComputeGas += gc.VirtualComputeGas
+	c.VirtualStorageGas += gc.VirtualStorageGas
+}
@@ -0,0 +1,112 @@
+package gastrace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/model/derived"
+	"github.com/filecoin-project/lily/testutil"
+)
+
+func TestParseLevel(t *testing.T) {
+	l, err := ParseLevel("")
+	require.NoError(t, err)
+	assert.Equal(t, LevelMessage, l)
+
+	l, err = ParseLevel("call")
+	require.NoError(t, err)
+	assert.Equal(t, LevelCall, l)
+
+	_, err = ParseLevel("charge")
+	assert.Error(t, err)
+}
+
+func TestChargeAggregation(t *testing.T) {
+	et := &types.ExecutionTrace{
+		GasCharges: []*types.GasTrace{
+			{Name: "OnChainMessage", TotalGas: 10, ComputeGas: 4, StorageGas: 6},
+			{Name: "OnIpldGet", TotalGas: 2, ComputeGas: 2},
+		},
+		Subcalls: []types.ExecutionTrace{
+			{
+				GasCharges: []*types.GasTrace{
+					{Name: "OnIpldGet", TotalGas: 3, ComputeGas: 3},
+				},
+			},
+		},
+	}
+
+	aggregate := func(level Level) derived.MessageGasChargeList {
+		a := &chargeAggregator{index: map[chargeKey]*derived.MessageGasCharge{}}
+		a.addTrace(et, level)
+		return a.out
+	}
+
+	t.Run("message", func(t *testing.T) {
+		out := aggregate(LevelMessage)
+		require.Len(t, out, 2)
+		assert.Equal(t, "OnChainMessage", out[0].Name)
+		assert.EqualValues(t, AllCalls, out[0].Call)
+		assert.Equal(t, "OnIpldGet", out[1].Name)
+		assert.EqualValues(t, 2, out[1].Count)
+		assert.EqualValues(t, 5, out[1].TotalGas)
+		assert.EqualValues(t, 5, out[1].ComputeGas)
+	})
+
+	t.Run("call", func(t *testing.T) {
+		out := aggregate(LevelCall)
+		require.Len(t, out, 3)
+		assert.EqualValues(t, 0, out[1].Call)
+		assert.EqualValues(t, 2, out[1].TotalGas)
+		assert.Equal(t, "OnIpldGet", out[2].Name)
+		assert.EqualValues(t, 1, out[2].Call)
+		assert.EqualValues(t, 3, out[2].TotalGas)
+	})
+}
+
+func TestNoGasCharges(t *testing.T) {
+	message := func(nonce uint64, et types.ExecutionTrace) *lens.MessageExecution {
+		from, _ := address.NewIDAddress(100)
+		to, _ := address.NewIDAddress(1000)
+		msg := &types.Message{From: from, To: to, Value: big.Zero(), Nonce: nonce}
+		return &lens.MessageExecution{
+			Cid:       msg.Cid(),
+			StateRoot: testutil.RandomCid(),
+			Height:    10,
+			Message:   msg,
+			Ret:       &vm.ApplyRet{ExecutionTrace: et},
+		}
+	}
+
+	ts := testutil.FakeTipset(t)
+
+	// a message that failed before execution has an empty trace
+	failed := message(0, types.ExecutionTrace{})
+	executed := message(1, types.ExecutionTrace{
+		GasCharges: []*types.GasTrace{{Name: "OnChainMessage", TotalGas: 10}},
+	})
+
+	t.Run("skips messages without charges", func(t *testing.T) {
+		out, _, err := NewTask(LevelMessage).ProcessMessageExecutions(context.Background(), nil, ts, ts, []*lens.MessageExecution{failed, executed})
+		require.NoError(t, err)
+		charges, ok := out.(derived.MessageGasChargeList)
+		require.True(t, ok)
+		require.Len(t, charges, 1)
+		assert.Equal(t, executed.Cid.String(), charges[0].Message)
+	})
+
+	t.Run("fails when no message has charges", func(t *testing.T) {
+		_, _, err := NewTask(LevelMessage).ProcessMessageExecutions(context.Background(), nil, ts, ts, []*lens.MessageExecution{failed})
+		require.Error(t, err)
+		assert.True(t, xerrors.Is(err, ErrNoGasCharges))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/filecoin-project/lily/chain/actors/adt"
+	"github.com/filecoin-project/lily/chain/actors/builtin"
 	"github.com/filecoin-project/lily/lens"
 	"github.com/filecoin-project/lily/lens/util"
 	"github.com/filecoin-project/lily/model"
@@ -40,9 +41,10 @@ func (p *Task) ProcessMessageExecutions(ctx context.Context, store adt.Store, ts
 	}
 
 	var (
-		internalResult       = make(messagemodel.InternalMessageList, 0, len(mex))
-		internalParsedResult = make(messagemodel.InternalParsedMessageList, 0, len(mex))
-		errorsDetected       = make([]*messages.MessageError, 0) // we don't know the cap since mex is recursive in nature.
+		internalResult        = make(messagemodel.InternalMessageList, 0, len(mex))
+		internalParsedResult  = make(messagemodel.InternalParsedMessageList, 0, len(mex))
+		internalReceiptResult = make(messagemodel.InternalParsedReceiptList, 0, len(mex))
+		errorsDetected        = make([]*messages.MessageError, 0) // we don't know the cap since mex is recursive in nature.
 	)
 
 	for _, m := range mex {
@@ -74,6 +76,8 @@ func (p *Task) ProcessMessageExecutions(ctx context.Context, store adt.Store, ts
 				Method:        uint64(m.Message.Method),
 				ExitCode:      int64(m.Ret.ExitCode),
 				GasUsed:       m.Ret.GasUsed,
+				MethodName:    builtin.MethodName(m.ToActorCode, m.Message.Method),
+				ExitCodeName:  builtin.ExitCodeName(m.ToActorCode, m.Ret.ExitCode),
 			})
 			method, params, err := util.MethodAndParamsForMessage(m.Message, m.ToActorCode)
 			if err != nil {
@@ -91,6 +95,24 @@ func (p *Task) ProcessMessageExecutions(ctx context.Context, store adt.Store, ts
 				Method: method,
 				Params: params,
 			})
+			if m.Ret.ExitCode.IsSuccess() && len(m.Ret.Return) > 0 {
+				rtn, err := messages.ParseReturn(m.Ret.Return, int64(m.Message.Method), m.ToActorCode)
+				if err == nil {
+					internalReceiptResult = append(internalReceiptResult, &messagemodel.InternalParsedReceipt{
+						Height:    int64(m.Height),
+						Cid:       m.Cid.String(),
+						StateRoot: m.StateRoot.String(),
+						ExitCode:  int64(m.Ret.ExitCode),
+						Method:    method,
+						Return:    rtn,
+					})
+				} else {
+					errorsDetected = append(errorsDetected, &messages.MessageError{
+						Cid:   m.Cid,
+						Error: xerrors.Errorf("failed parse return for message: %w", err).Error(),
+					})
+				}
+			}
 		}
 
 		// TODO(frrist): this code is commented out as it collects all internal message sent through the VM.
@@ -162,6 +184,7 @@ func (p *Task) ProcessMessageExecutions(ctx context.Context, store adt.Store, ts
 
 		internalResult,
 		internalParsedResult,
+		internalReceiptResult,
 	}, report, nil
 }
 
@@ -1,9 +1,11 @@
 package fcjson
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/polydawn/refmt/json"
+	"github.com/polydawn/refmt/tok"
 
 	ipld "github.com/ipld/go-ipld-prime"
 	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
@@ -26,3 +28,36 @@ func (d *DagMarshaler) Encoder(n ipld.Node, w io.Writer) error {
 		Indent: []byte{'\t'},
 	}))
 }
+
+// FieldsEncoder encodes nodes as a map from each of names to the node at the same position, keeping their order. It is
+// used for values that are represented as tuples and have no type in the generated schema.
+func FieldsEncoder(names []string, nodes []ipld.Node, w io.Writer) error {
+	if len(names) != len(nodes) {
+		return fmt.Errorf("got %d names for %d fields", len(names), len(nodes))
+	}
+
+	sink := json.NewEncoder(w, json.EncodeOptions{
+		Line:   []byte{'\n'},
+		Indent: []byte{'\t'},
+	})
+
+	var tk tok.Token
+	tk.Type = tok.TMapOpen
+	tk.Length = len(nodes)
+	if _, err := sink.Step(&tk); err != nil {
+		return err
+	}
+	for i, n := range nodes {
+		tk.Type = tok.TString
+		tk.Str = names[i]
+		if _, err := sink.Step(&tk); err != nil {
+			return err
+		}
+		if err := Marshal(n, sink); err != nil {
+			return err
+		}
+	}
+	tk.Type = tok.TMapClose
+	_, err := sink.Step(&tk)
+	return err
+}
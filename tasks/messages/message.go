@@ -28,10 +28,14 @@ import (
 
 var log = logging.Logger("lily/task/messages")
 
-type Task struct{}
+type Task struct {
+	node lens.API
+}
 
-func NewTask() *Task {
-	return &Task{}
+func NewTask(node lens.API) *Task {
+	return &Task{
+		node: node,
+	}
 }
 
 // Note that pts is the parent tipset containing the messages, ts is the following tipset containing the receipts
@@ -51,6 +55,8 @@ func (p *Task) ProcessMessages(ctx context.Context, ts *types.TipSet, pts *types
 		messageResults       = make(messagemodel.Messages, 0, len(emsgs))
 		receiptResults       = make(messagemodel.Receipts, 0, len(emsgs))
 		parsedMessageResults = make(messagemodel.ParsedMessages, 0, len(emsgs))
+		parsedReceiptResults = make(messagemodel.ParsedReceipts, 0, len(emsgs))
+		multisigInnerResults = make(messagemodel.MultisigInnerMessageList, 0) // no initial size capacity since multisig proposals are rare
 		gasOutputsResults    = make(derivedmodel.GasOutputsList, 0, len(emsgs))
 		errorsDetected       = make([]*MessageError, 0, len(emsgs))
 	)
@@ -58,6 +64,8 @@ func (p *Task) ProcessMessages(ctx context.Context, ts *types.TipSet, pts *types
 	var (
 		exeMsgSeen        = make(map[cid.Cid]bool, len(emsgs))
 		blkMsgSeen        = make(map[cid.Cid]bool)
+		methodNames       = make(map[string]string, len(emsgs)) // method names of executed messages keyed by cid
+		proposals         = multisigProposals{}                 // multisig proposals of the tipset, resolved by later approvals
 		totalGasLimit     int64
 		totalUniqGasLimit int64
 	)
@@ -188,8 +196,11 @@ func (p *Task) ProcessMessages(ctx context.Context, ts *types.TipSet, pts *types
 			Idx:       int(m.Index),
 			ExitCode:  int64(m.Receipt.ExitCode),
 			GasUsed:   m.Receipt.GasUsed,
+
+			ExitCodeName: builtin.ExitCodeName(m.ToActorCode, m.Receipt.ExitCode),
 		}
 		receiptResults = append(receiptResults, rcpt)
+		methodNames[rcpt.Message] = builtin.MethodName(m.ToActorCode, m.Message.Method)
 
 		actorName := builtin.ActorNameByCode(m.ToActorCode)
 		gasOutput := &derivedmodel.GasOutputs{
@@ -242,6 +253,50 @@ func (p *Task) ProcessMessages(ctx context.Context, ts *types.TipSet, pts *types
 				})
 			}
 		}
+
+		// the return value is decoded even when the params could not be
+		if m.Receipt.ExitCode.IsSuccess() && len(m.Receipt.Return) > 0 {
+			if method == "" {
+				method = methodNames[rcpt.Message]
+			}
+			rtn, err := ParseReturn(m.Receipt.Return, int64(m.Message.Method), m.ToActorCode)
+			if err == nil {
+				parsedReceiptResults = append(parsedReceiptResults, &messagemodel.ParsedReceipt{
+					Height:    rcpt.Height,
+					Message:   rcpt.Message,
+					StateRoot: rcpt.StateRoot,
+					Idx:       rcpt.Idx,
+					ExitCode:  rcpt.ExitCode,
+					Method:    method,
+					Return:    rtn,
+				})
+			} else {
+				errorsDetected = append(errorsDetected, &MessageError{
+					Cid:   m.Cid,
+					Error: xerrors.Errorf("failed to parse message return: %w", err).Error(),
+				})
+			}
+		}
+
+		if builtin.IsMultisigActor(m.ToActorCode) && m.Receipt.ExitCode.IsSuccess() {
+			inner, err := p.parseMultisigMessage(ctx, ts, pts, m, proposals)
+			if err != nil {
+				errorsDetected = append(errorsDetected, &MessageError{
+					Cid:   m.Cid,
+					Error: xerrors.Errorf("failed to parse multisig inner message: %w", err).Error(),
+				})
+			}
+			multisigInnerResults = append(multisigInnerResults, inner...)
+		}
+	}
+
+	// the code of the receiving actor is only known for executed messages
+	for _, msg := range messageResults {
+		if name, ok := methodNames[msg.Cid]; ok {
+			msg.MethodName = name
+		} else if msg.Method == 0 {
+			msg.MethodName = "Send"
+		}
 	}
 
 	newBaseFee := store.ComputeNextBaseFee(pts.Blocks()[0].ParentBaseFee, totalUniqGasLimit, len(pts.Blocks()), pts.Height())
@@ -272,6 +327,8 @@ func (p *Task) ProcessMessages(ctx context.Context, ts *types.TipSet, pts *types
 		receiptResults,
 		blockMessageResults,
 		parsedMessageResults,
+		parsedReceiptResults,
+		multisigInnerResults,
 		gasOutputsResults,
 		messageGasEconomyResult,
 	}, report, nil
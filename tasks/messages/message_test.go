@@ -107,7 +107,7 @@ func TestParseMessageParams(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			task := NewTask()
+			task := NewTask(nil)
 
 			to, _ := address.NewIDAddress(1)
 			from, _ := address.NewIDAddress(2)
@@ -0,0 +1,273 @@
+package messages
+
+import (
+	"bytes"
+	"fmt"
+
+	sa0builtin "github.com/filecoin-project/specs-actors/actors/builtin"
+	sa2builtin "github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	sa3builtin "github.com/filecoin-project/specs-actors/v3/actors/builtin"
+	sa4builtin "github.com/filecoin-project/specs-actors/v4/actors/builtin"
+	sa5builtin "github.com/filecoin-project/specs-actors/v5/actors/builtin"
+	"github.com/ipfs/go-cid"
+	"github.com/ipld/go-ipld-prime"
+	"github.com/ipld/go-ipld-prime/codec/dagcbor"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/lily/tasks/messages/fcjson"
+	"github.com/filecoin-project/lily/tasks/messages/types"
+)
+
+// The return tables use the same method tables as parameters so that return values are decoded in the same way.
+// Return values without a type in the generated schema are decoded by the fields listed in returnFields, or
+// generically when they are not listed.
+var accountReturnTable = methodtable{
+	2: methodMeta{"PubkeyAddress", types.Type.Address__Repr},
+}
+
+var initReturnTable = methodtable{
+	2: methodMeta{"ExecReturn", types.Type.Any__Repr},
+}
+
+var marketReturnTable = methodtable{
+	4: methodMeta{"PublishStorageDealsReturn", types.Type.Any__Repr},
+	5: methodMeta{"VerifyDealsForActivationReturn", types.Type.Any__Repr},
+}
+
+var minerReturnTable = methodtable{
+	2: methodMeta{"GetControlAddressesReturn", types.Type.Any__Repr},
+}
+
+var multisigReturnTable = methodtable{
+	2: methodMeta{"ProposeReturn", types.Type.Any__Repr},
+	3: methodMeta{"ApproveReturn", types.Type.Any__Repr},
+}
+
+var powerReturnTable = methodtable{
+	2: methodMeta{"CreateMinerReturn", types.Type.Any__Repr},
+	9: methodMeta{"CurrentTotalPowerReturn", types.Type.MessageParamsPowerCurrentTotal__Repr},
+}
+
+var rewardReturnTable = methodtable{
+	3: methodMeta{"ThisEpochRewardReturn", types.Type.Any__Repr},
+}
+
+// returnField is a field of a return value represented as a tuple, decoded with the prototype of its type in the
+// generated schema.
+type returnField struct {
+	Name string
+	ipld.NodePrototype
+}
+
+// returnFields lists the fields of the return values that have no type in the generated schema, keyed by the name of
+// the return value in the return tables. Return values whose fields changed between actors versions have a layout for
+// each version, the layout with as many fields as the encoded tuple is used.
+var returnFields = map[string][][]returnField{
+	"ExecReturn": {{
+		{"IDAddress", types.Type.Address__Repr},
+		{"RobustAddress", types.Type.Address__Repr},
+	}},
+	"PublishStorageDealsReturn": {{
+		{"IDs", types.Type.List__DealID__Repr},
+	}},
+	"VerifyDealsForActivationReturn": {
+		// v0
+		{
+			{"DealWeight", types.Type.BigInt__Repr},
+			{"VerifiedDealWeight", types.Type.BigInt__Repr},
+		},
+		// v2 to v4
+		{
+			{"DealWeight", types.Type.BigInt__Repr},
+			{"VerifiedDealWeight", types.Type.BigInt__Repr},
+			{"DealSpace", types.Type.Int__Repr},
+		},
+		// v5
+		{
+			{"Sectors", types.Type.Any__Repr},
+		},
+	},
+	"GetControlAddressesReturn": {{
+		{"Owner", types.Type.Address__Repr},
+		{"Worker", types.Type.Address__Repr},
+		{"ControlAddrs", types.Type.List__Address__Repr},
+	}},
+	"ProposeReturn": {{
+		{"TxnID", types.Type.Int__Repr},
+		{"Applied", types.Type.Bool__Repr},
+		{"Code", types.Type.Int__Repr},
+		{"Ret", types.Type.Bytes__Repr},
+	}},
+	"ApproveReturn": {{
+		{"Applied", types.Type.Bool__Repr},
+		{"Code", types.Type.Int__Repr},
+		{"Ret", types.Type.Bytes__Repr},
+	}},
+	"CreateMinerReturn": {{
+		{"IDAddress", types.Type.Address__Repr},
+		{"RobustAddress", types.Type.Address__Repr},
+	}},
+	"ThisEpochRewardReturn": {
+		// v0
+		{
+			{"ThisEpochReward", types.Type.BigInt__Repr},
+			{"ThisEpochRewardSmoothed", types.Type.V0FilterEstimate__Repr},
+			{"ThisEpochBaselinePower", types.Type.BigInt__Repr},
+		},
+		// v2 onwards
+		{
+			{"ThisEpochRewardSmoothed", types.Type.V0FilterEstimate__Repr},
+			{"ThisEpochBaselinePower", types.Type.BigInt__Repr},
+		},
+	},
+}
+
+var messageReturnTable = map[cid.Cid]methodtable{
+	sa0builtin.AccountActorCodeID:          accountReturnTable,
+	sa0builtin.CronActorCodeID:             {},
+	sa0builtin.InitActorCodeID:             initReturnTable,
+	sa0builtin.MultisigActorCodeID:         multisigReturnTable,
+	sa0builtin.PaymentChannelActorCodeID:   {},
+	sa0builtin.RewardActorCodeID:           rewardReturnTable,
+	sa0builtin.StorageMarketActorCodeID:    marketReturnTable,
+	sa0builtin.StorageMinerActorCodeID:     minerReturnTable,
+	sa0builtin.StoragePowerActorCodeID:     powerReturnTable,
+	sa0builtin.SystemActorCodeID:           {},
+	sa0builtin.VerifiedRegistryActorCodeID: {},
+
+	// v2
+	sa2builtin.AccountActorCodeID:          accountReturnTable,
+	sa2builtin.CronActorCodeID:             {},
+	sa2builtin.InitActorCodeID:             initReturnTable,
+	sa2builtin.MultisigActorCodeID:         multisigReturnTable,
+	sa2builtin.PaymentChannelActorCodeID:   {},
+	sa2builtin.RewardActorCodeID:           rewardReturnTable,
+	sa2builtin.StorageMarketActorCodeID:    marketReturnTable,
+	sa2builtin.StorageMinerActorCodeID:     minerReturnTable,
+	sa2builtin.StoragePowerActorCodeID:     powerReturnTable,
+	sa2builtin.SystemActorCodeID:           {},
+	sa2builtin.VerifiedRegistryActorCodeID: {},
+
+	// v3
+	sa3builtin.AccountActorCodeID:          accountReturnTable,
+	sa3builtin.CronActorCodeID:             {},
+	sa3builtin.InitActorCodeID:             initReturnTable,
+	sa3builtin.MultisigActorCodeID:         multisigReturnTable,
+	sa3builtin.PaymentChannelActorCodeID:   {},
+	sa3builtin.RewardActorCodeID:           rewardReturnTable,
+	sa3builtin.StorageMarketActorCodeID:    marketReturnTable,
+	sa3builtin.StorageMinerActorCodeID:     minerReturnTable,
+	sa3builtin.StoragePowerActorCodeID:     powerReturnTable,
+	sa3builtin.SystemActorCodeID:           {},
+	sa3builtin.VerifiedRegistryActorCodeID: {},
+
+	// v4
+	sa4builtin.AccountActorCodeID:          accountReturnTable,
+	sa4builtin.CronActorCodeID:             {},
+	sa4builtin.InitActorCodeID:             initReturnTable,
+	sa4builtin.MultisigActorCodeID:         multisigReturnTable,
+	sa4builtin.PaymentChannelActorCodeID:   {},
+	sa4builtin.RewardActorCodeID:           rewardReturnTable,
+	sa4builtin.StorageMarketActorCodeID:    marketReturnTable,
+	sa4builtin.StorageMinerActorCodeID:     minerReturnTable,
+	sa4builtin.StoragePowerActorCodeID:     powerReturnTable,
+	sa4builtin.SystemActorCodeID:           {},
+	sa4builtin.VerifiedRegistryActorCodeID: {},
+
+	// v5
+	sa5builtin.AccountActorCodeID:          accountReturnTable,
+	sa5builtin.CronActorCodeID:             {},
+	sa5builtin.InitActorCodeID:             initReturnTable,
+	sa5builtin.MultisigActorCodeID:         multisigReturnTable,
+	sa5builtin.PaymentChannelActorCodeID:   {},
+	sa5builtin.RewardActorCodeID:           rewardReturnTable,
+	sa5builtin.StorageMarketActorCodeID:    marketReturnTable,
+	sa5builtin.StorageMinerActorCodeID:     minerReturnTable,
+	sa5builtin.StoragePowerActorCodeID:     powerReturnTable,
+	sa5builtin.SystemActorCodeID:           {},
+	sa5builtin.VerifiedRegistryActorCodeID: {},
+}
+
+// ParseReturn decodes the return value of a message sent to an actor of type destType and encodes it as json, in the
+// same way as ParseParams decodes parameters. Return values of methods without an entry in the return tables are
+// decoded generically. An empty return value is encoded as an empty string.
+func ParseReturn(ret []byte, method int64, destType cid.Cid) (string, error) {
+	rtnTable, ok := messageReturnTable[destType]
+	if !ok {
+		return "", fmt.Errorf("unknown return values for %s", destType)
+	}
+
+	if len(ret) == 0 {
+		return "", nil
+	}
+
+	proto := ipld.NodePrototype(types.Type.Any__Repr)
+	name := "Unknown"
+	if rtn, ok := rtnTable[method]; ok {
+		proto = rtn.NodePrototype
+		name = rtn.Name
+	}
+
+	if layouts, ok := returnFields[name]; ok {
+		return parseReturnFields(ret, layouts, name, method, destType)
+	}
+
+	builder := proto.NewBuilder()
+	if err := dagcbor.Decoder(builder, bytes.NewBuffer(ret)); err != nil {
+		return "", fmt.Errorf("cbor decode into %s (%s.%d) failed: %v", name, destType, method, err)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := fcjson.Encoder(builder.Build(), buf); err != nil {
+		return "", fmt.Errorf("json encode: %v", err)
+	}
+
+	return string(bytes.ReplaceAll(bytes.ToValidUTF8(buf.Bytes(), []byte{}), []byte{0x00}, []byte{})), nil
+}
+
+// parseReturnFields decodes a return value represented as a tuple using the layout of its fields with as many fields
+// as the tuple and encodes it as a json object keyed by the names of the fields.
+func parseReturnFields(ret []byte, layouts [][]returnField, name string, method int64, destType cid.Cid) (string, error) {
+	r := bytes.NewReader(ret)
+	maj, count, err := cbg.CborReadHeader(r)
+	if err != nil {
+		return "", fmt.Errorf("cbor decode into %s (%s.%d) failed: %v", name, destType, method, err)
+	}
+	if maj != cbg.MajArray {
+		return "", fmt.Errorf("cbor decode into %s (%s.%d) failed: expected a tuple", name, destType, method)
+	}
+
+	var fields []returnField
+	for _, l := range layouts {
+		if uint64(len(l)) == count {
+			fields = l
+			break
+		}
+	}
+	if fields == nil {
+		return "", fmt.Errorf("cbor decode into %s (%s.%d) failed: unexpected number of fields %d", name, destType, method, count)
+	}
+
+	names := make([]string, len(fields))
+	nodes := make([]ipld.Node, len(fields))
+	for i, f := range fields {
+		var raw cbg.Deferred
+		if err := raw.UnmarshalCBOR(r); err != nil {
+			return "", fmt.Errorf("cbor decode into %s (%s.%d) failed: field %s: %v", name, destType, method, f.Name, err)
+		}
+
+		builder := f.NodePrototype.NewBuilder()
+		if err := dagcbor.Decoder(builder, bytes.NewBuffer(raw.Raw)); err != nil {
+			return "", fmt.Errorf("cbor decode into %s (%s.%d) failed: field %s: %v", name, destType, method, f.Name, err)
+		}
+		names[i] = f.Name
+		nodes[i] = builder.Build()
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := fcjson.FieldsEncoder(names, nodes, buf); err != nil {
+		return "", fmt.Errorf("json encode: %v", err)
+	}
+
+	return string(bytes.ReplaceAll(bytes.ToValidUTF8(buf.Bytes(), []byte{}), []byte{0x00}, []byte{})), nil
+}
@@ -0,0 +1,103 @@
+package messages
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	sa0builtin "github.com/filecoin-project/specs-actors/actors/builtin"
+	sa0market "github.com/filecoin-project/specs-actors/actors/builtin/market"
+	sa5builtin "github.com/filecoin-project/specs-actors/v5/actors/builtin"
+	sa5init "github.com/filecoin-project/specs-actors/v5/actors/builtin/init"
+	sa5multisig "github.com/filecoin-project/specs-actors/v5/actors/builtin/multisig"
+	sa5reward "github.com/filecoin-project/specs-actors/v5/actors/builtin/reward"
+	smoothing5 "github.com/filecoin-project/specs-actors/v5/actors/util/smoothing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReturnTableCoverage(t *testing.T) {
+	for code := range messageParamTable {
+		_, ok := messageReturnTable[code]
+		assert.True(t, ok, "missing return table for actor code %s", code)
+	}
+}
+
+func TestParseReturn(t *testing.T) {
+	t.Run("publish storage deals", func(t *testing.T) {
+		ret := sa0market.PublishStorageDealsReturn{IDs: []abi.DealID{1, 2}}
+		buf := new(bytes.Buffer)
+		require.NoError(t, ret.MarshalCBOR(buf))
+
+		rtn, err := ParseReturn(buf.Bytes(), int64(sa0builtin.MethodsMarket.PublishStorageDeals), sa0builtin.StorageMarketActorCodeID)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"IDs":[1,2]}`, rtn)
+	})
+
+	t.Run("propose", func(t *testing.T) {
+		ret := sa5multisig.ProposeReturn{TxnID: 7, Applied: true, Code: exitcode.Ok, Ret: []byte{0x01}}
+		buf := new(bytes.Buffer)
+		require.NoError(t, ret.MarshalCBOR(buf))
+
+		rtn, err := ParseReturn(buf.Bytes(), int64(sa5builtin.MethodsMultisig.Propose), sa5builtin.MultisigActorCodeID)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"TxnID":7,"Applied":true,"Code":0,"Ret":"AQ=="}`, rtn)
+	})
+
+	t.Run("exec", func(t *testing.T) {
+		id, err := address.NewIDAddress(1000)
+		require.NoError(t, err)
+		robust, err := address.NewActorAddress([]byte("robust"))
+		require.NoError(t, err)
+		ret := sa5init.ExecReturn{IDAddress: id, RobustAddress: robust}
+		buf := new(bytes.Buffer)
+		require.NoError(t, ret.MarshalCBOR(buf))
+
+		rtn, err := ParseReturn(buf.Bytes(), int64(sa5builtin.MethodsInit.Exec), sa5builtin.InitActorCodeID)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"IDAddress":"`+id.String()+`","RobustAddress":"`+robust.String()+`"}`, rtn)
+	})
+
+	t.Run("this epoch reward", func(t *testing.T) {
+		ret := sa5reward.ThisEpochRewardReturn{
+			ThisEpochRewardSmoothed: smoothing5.FilterEstimate{PositionEstimate: big.NewInt(10), VelocityEstimate: big.NewInt(2)},
+			ThisEpochBaselinePower:  big.NewInt(100),
+		}
+		buf := new(bytes.Buffer)
+		require.NoError(t, ret.MarshalCBOR(buf))
+
+		rtn, err := ParseReturn(buf.Bytes(), int64(sa5builtin.MethodsReward.ThisEpochReward), sa5builtin.RewardActorCodeID)
+		require.NoError(t, err)
+
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(rtn), &fields))
+		assert.Equal(t, "100", fields["ThisEpochBaselinePower"])
+		assert.Contains(t, fields, "ThisEpochRewardSmoothed")
+	})
+
+	t.Run("pubkey address", func(t *testing.T) {
+		addr, err := address.NewIDAddress(1000)
+		require.NoError(t, err)
+		buf := new(bytes.Buffer)
+		require.NoError(t, addr.MarshalCBOR(buf))
+
+		rtn, err := ParseReturn(buf.Bytes(), int64(sa0builtin.MethodsAccount.PubkeyAddress), sa0builtin.AccountActorCodeID)
+		require.NoError(t, err)
+		assert.JSONEq(t, `"`+addr.String()+`"`, rtn)
+	})
+
+	t.Run("empty return", func(t *testing.T) {
+		rtn, err := ParseReturn(nil, int64(sa0builtin.MethodsMarket.PublishStorageDeals), sa0builtin.StorageMarketActorCodeID)
+		require.NoError(t, err)
+		assert.Equal(t, "", rtn)
+	})
+
+	t.Run("invalid return", func(t *testing.T) {
+		_, err := ParseReturn([]byte{0xff}, int64(sa0builtin.MethodsMarket.PublishStorageDeals), sa0builtin.StorageMarketActorCodeID)
+		require.Error(t, err)
+	})
+}
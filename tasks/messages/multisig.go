@@ -0,0 +1,261 @@
+package messages
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/lotus/chain/types"
+	multisig0 "github.com/filecoin-project/specs-actors/actors/builtin/multisig"
+	"github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin"
+	"github.com/filecoin-project/lily/chain/actors/builtin/multisig"
+	"github.com/filecoin-project/lily/lens"
+	messagemodel "github.com/filecoin-project/lily/model/messages"
+)
+
+// multisigCall is a call proposed to or approved by a multisig actor.
+type multisigCall struct {
+	multisig address.Address
+	txnID    int64
+	applied  bool
+	ret      []byte // return value of the call, only set when the call was applied
+
+	to     address.Address
+	value  abi.TokenAmount
+	method abi.MethodNum
+	params []byte
+}
+
+// multisigTxn identifies a transaction of a multisig actor by the actor's ID address and the transaction id.
+type multisigTxn struct {
+	multisig address.Address
+	id       int64
+}
+
+// multisigProposals holds the calls proposed to multisig actors by the messages of a tipset parsed so far. An approval
+// or cancellation executed in the same tipset as the proposal cannot find the transaction in the state the tipset was
+// applied to and, once it is approved or cancelled, not in the resulting state either.
+type multisigProposals map[multisigTxn]*multisigCall
+
+// parseMultisigMessage decodes the call carried by a successful propose, approve or cancel message sent to a multisig
+// actor. When the call was applied and is itself a proposal or approval sent to another multisig actor, the call
+// carried by that is decoded as well, until the final recipient is reached. Messages must be parsed in the order they
+// were executed so that proposals are recorded before the approvals of the same tipset that resolve them.
+func (p *Task) parseMultisigMessage(ctx context.Context, ts, pts *types.TipSet, m *lens.ExecutedMessage, proposals multisigProposals) (messagemodel.MultisigInnerMessageList, error) {
+	call, err := p.multisigCallOf(ctx, ts, pts, m.Message.To, m.Message.Method, m.Message.Params, m.Receipt.Return, proposals)
+	if err != nil {
+		return nil, err
+	}
+
+	var out messagemodel.MultisigInnerMessageList
+	for depth := 0; call != nil; depth++ {
+		code, err := p.actorCode(ctx, call.to, ts, pts)
+		if err != nil {
+			return out, err
+		}
+
+		method, params, err := p.parseMessageParams(&types.Message{
+			To:     call.to,
+			Value:  call.value,
+			Method: call.method,
+			Params: call.params,
+		}, code)
+		if err != nil {
+			return out, xerrors.Errorf("parse params of call to %s: %w", call.to, err)
+		}
+
+		out = append(out, &messagemodel.MultisigInnerMessage{
+			Height:        int64(m.Height),
+			Cid:           m.Cid.String(),
+			Depth:         depth,
+			StateRoot:     pts.ParentState().String(),
+			MultisigID:    call.multisig.String(),
+			TransactionID: call.txnID,
+			Applied:       call.applied,
+			To:            call.to.String(),
+			Value:         call.value.String(),
+			Method:        method,
+			Params:        params,
+		})
+
+		// a call that was not applied has not been executed yet, so any proposal it carries does not exist
+		if !call.applied || !builtin.IsMultisigActor(code) {
+			break
+		}
+		call, err = p.multisigCallOf(ctx, ts, pts, call.to, call.method, call.params, call.ret, proposals)
+		if err != nil {
+			return out, err
+		}
+	}
+
+	return out, nil
+}
+
+// multisigCallOf returns the call carried by a propose, approve or cancel sent to the multisig actor msig, or nil for any
+// other method. Proposals that were not applied are added to proposals.
+func (p *Task) multisigCallOf(ctx context.Context, ts, pts *types.TipSet, msig address.Address, method abi.MethodNum, params []byte, ret []byte, proposals multisigProposals) (*multisigCall, error) {
+	switch method {
+	case multisig.Methods.Propose:
+		// these types are the same between v0 and v5
+		var pp multisig.ProposeParams
+		if err := pp.UnmarshalCBOR(bytes.NewReader(params)); err != nil {
+			return nil, xerrors.Errorf("failed to decode propose params: %w", err)
+		}
+		var pr multisig.ProposeReturn
+		if err := pr.UnmarshalCBOR(bytes.NewReader(ret)); err != nil {
+			return nil, xerrors.Errorf("failed to decode propose return value: %w", err)
+		}
+
+		call := &multisigCall{
+			multisig: msig,
+			txnID:    int64(pr.TxnID),
+			applied:  pr.Applied,
+			ret:      pr.Ret,
+			to:       pp.To,
+			value:    pp.Value,
+			method:   pp.Method,
+			params:   pp.Params,
+		}
+		if !call.applied {
+			proposals[multisigTxn{multisig: p.multisigID(ctx, ts, msig), id: call.txnID}] = call
+		}
+		return call, nil
+
+	case multisig.Methods.Approve:
+		// these types are the same between v0 and v5
+		var ap multisig0.TxnIDParams
+		if err := ap.UnmarshalCBOR(bytes.NewReader(params)); err != nil {
+			return nil, xerrors.Errorf("failed to decode approve params: %w", err)
+		}
+		var ar multisig0.ApproveReturn
+		if err := ar.UnmarshalCBOR(bytes.NewReader(ret)); err != nil {
+			return nil, xerrors.Errorf("failed to decode approve return value: %w", err)
+		}
+
+		txn, err := p.pendingTransaction(ctx, ts, pts, msig, int64(ap.ID), proposals)
+		if err != nil {
+			return nil, err
+		}
+
+		return &multisigCall{
+			multisig: msig,
+			txnID:    int64(ap.ID),
+			applied:  ar.Applied,
+			ret:      ar.Ret,
+			to:       txn.To,
+			value:    txn.Value,
+			method:   txn.Method,
+			params:   txn.Params,
+		}, nil
+
+	case multisig.Methods.Cancel:
+		// these types are the same between v0 and v5
+		var cp multisig0.TxnIDParams
+		if err := cp.UnmarshalCBOR(bytes.NewReader(params)); err != nil {
+			return nil, xerrors.Errorf("failed to decode cancel params: %w", err)
+		}
+
+		txn, err := p.pendingTransaction(ctx, ts, pts, msig, int64(cp.ID), proposals)
+		if err != nil {
+			return nil, err
+		}
+
+		// a cancelled call is never applied
+		return &multisigCall{
+			multisig: msig,
+			txnID:    int64(cp.ID),
+			to:       txn.To,
+			value:    txn.Value,
+			method:   txn.Method,
+			params:   txn.Params,
+		}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// pendingTransaction returns the pending transaction with the given id of the multisig actor msig. The transaction is
+// taken from the proposals made earlier in the tipset, the state the messages of pts were applied to or, failing
+// those, the resulting state.
+func (p *Task) pendingTransaction(ctx context.Context, ts, pts *types.TipSet, msig address.Address, id int64, proposals multisigProposals) (*multisig.Transaction, error) {
+	if call, ok := proposals[multisigTxn{multisig: p.multisigID(ctx, ts, msig), id: id}]; ok {
+		return &multisig.Transaction{
+			To:     call.to,
+			Value:  call.value,
+			Method: call.method,
+			Params: call.params,
+		}, nil
+	}
+
+	for _, tsk := range []types.TipSetKey{pts.Key(), ts.Key()} {
+		txn, err := p.stateTransaction(ctx, msig, id, tsk)
+		if err != nil {
+			return nil, err
+		}
+		if txn != nil {
+			return txn, nil
+		}
+	}
+	return nil, xerrors.Errorf("pending transaction %d not found", id)
+}
+
+// stateTransaction returns the pending transaction with the given id from the state of the multisig actor msig in the
+// parent state of tsk, or nil if the actor or transaction does not exist in that state.
+func (p *Task) stateTransaction(ctx context.Context, msig address.Address, id int64, tsk types.TipSetKey) (*multisig.Transaction, error) {
+	act, err := p.node.StateGetActor(ctx, msig, tsk)
+	if err != nil {
+		if xerrors.Is(err, types.ErrActorNotFound) {
+			// the actor may have been created in the tipset
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("failed to load multisig actor: %w", err)
+	}
+
+	st, err := multisig.Load(p.node.Store(), act)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load multisig actor state: %w", err)
+	}
+
+	var found *multisig.Transaction
+	if err := st.ForEachPendingTxn(func(txid int64, txn multisig.Transaction) error {
+		if txid == id {
+			found = &txn
+		}
+		return nil
+	}); err != nil {
+		return nil, xerrors.Errorf("failed to read pending transactions: %w", err)
+	}
+	return found, nil
+}
+
+// multisigID returns the ID address of the multisig actor msig so that proposals and approvals using different
+// addresses of the same actor match. The address is returned unchanged if it cannot be resolved.
+func (p *Task) multisigID(ctx context.Context, ts *types.TipSet, msig address.Address) address.Address {
+	if msig.Protocol() == address.ID {
+		return msig
+	}
+	id, err := p.node.StateLookupID(ctx, msig, ts.Key())
+	if err != nil {
+		return msig
+	}
+	return id
+}
+
+// actorCode returns the code of the actor at addr. The actor is looked up in the state the messages of pts were
+// applied to and, since it may have been created by one of those messages, in the resulting state.
+func (p *Task) actorCode(ctx context.Context, addr address.Address, ts, pts *types.TipSet) (cid.Cid, error) {
+	act, err := p.node.StateGetActor(ctx, addr, pts.Key())
+	if err == nil {
+		return act.Code, nil
+	}
+	act, err = p.node.StateGetActor(ctx, addr, ts.Key())
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("failed to load actor %s: %w", addr, err)
+	}
+	return act.Code, nil
+}
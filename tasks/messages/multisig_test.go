@@ -0,0 +1,77 @@
+package messages
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	multisig0 "github.com/filecoin-project/specs-actors/actors/builtin/multisig"
+
+	"github.com/filecoin-project/lily/chain/actors/builtin/multisig"
+)
+
+func mustMarshal(t *testing.T, v interface {
+	MarshalCBOR(w io.Writer) error
+}) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	if err := v.MarshalCBOR(buf); err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestMultisigCallOfSameTipSet(t *testing.T) {
+	ctx := context.Background()
+	p := &Task{}
+
+	msig, err := address.NewIDAddress(1000)
+	if err != nil {
+		t.Fatalf("failed to create address: %v", err)
+	}
+	to, err := address.NewIDAddress(1001)
+	if err != nil {
+		t.Fatalf("failed to create address: %v", err)
+	}
+
+	proposals := multisigProposals{}
+
+	// the proposal is not applied so it is recorded for later messages of the tipset
+	propose, err := p.multisigCallOf(ctx, nil, nil, msig, multisig.Methods.Propose,
+		mustMarshal(t, &multisig.ProposeParams{To: to, Value: big.NewInt(10), Method: abi.MethodNum(0)}),
+		mustMarshal(t, &multisig.ProposeReturn{TxnID: 3}),
+		proposals)
+	if err != nil {
+		t.Fatalf("failed to parse propose: %v", err)
+	}
+	if propose.applied || propose.txnID != 3 {
+		t.Fatalf("unexpected propose call: %+v", propose)
+	}
+
+	// the approval resolves the transaction from the proposal without consulting any state
+	approve, err := p.multisigCallOf(ctx, nil, nil, msig, multisig.Methods.Approve,
+		mustMarshal(t, &multisig0.TxnIDParams{ID: 3}),
+		mustMarshal(t, &multisig0.ApproveReturn{Applied: true, Code: 0, Ret: []byte{}}),
+		proposals)
+	if err != nil {
+		t.Fatalf("failed to parse approve: %v", err)
+	}
+	if !approve.applied || approve.to != to || !approve.value.Equals(big.NewInt(10)) {
+		t.Fatalf("unexpected approve call: %+v", approve)
+	}
+
+	cancel, err := p.multisigCallOf(ctx, nil, nil, msig, multisig.Methods.Cancel,
+		mustMarshal(t, &multisig0.TxnIDParams{ID: 3}),
+		nil,
+		proposals)
+	if err != nil {
+		t.Fatalf("failed to parse cancel: %v", err)
+	}
+	if cancel.applied || cancel.to != to || cancel.txnID != 3 {
+		t.Fatalf("unexpected cancel call: %+v", cancel)
+	}
+}
@@ -0,0 +1,148 @@
+// Package networkversion provides a task for recording the network, actors and state tree versions in effect at each
+// epoch
+package networkversion
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/label"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors"
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/lens/util"
+	"github.com/filecoin-project/lily/model"
+	chainmodel "github.com/filecoin-project/lily/model/chain"
+	visormodel "github.com/filecoin-project/lily/model/visor"
+)
+
+type Task struct {
+	node    lens.API
+	network *util.Network
+
+	upgradesOnce sync.Once
+
+	// versions of the last tipset processed, reused as the parent's versions when following the chain
+	lastKey      types.TipSetKey
+	lastVersions *versions
+}
+
+func NewTask(node lens.API) *Task {
+	return &Task{
+		node:    node,
+		network: util.DefaultNetwork,
+	}
+}
+
+// ProcessTipSet records the versions in effect at ts when any of them differ from those in effect at its parent. The
+// upgrade schedule of the network is recorded with the first tipset processed.
+func (p *Task) ProcessTipSet(ctx context.Context, ts *types.TipSet) (model.Persistable, *visormodel.ProcessingReport, error) {
+	ctx, span := global.Tracer("").Start(ctx, "ProcessNetworkVersion")
+	if span.IsRecording() {
+		span.SetAttributes(label.String("tipset", ts.String()), label.Int64("height", int64(ts.Height())))
+	}
+	defer span.End()
+
+	report := &visormodel.ProcessingReport{
+		Height:    int64(ts.Height()),
+		StateRoot: ts.ParentState().String(),
+	}
+
+	cur, err := p.versions(ctx, ts.Height(), ts.ParentState())
+	if err != nil {
+		report.ErrorsDetected = xerrors.Errorf("get versions: %w", err)
+		return nil, report, nil
+	}
+
+	// the genesis tipset has no parent to compare against
+	changed := true
+	if ts.Height() > 0 {
+		prev := p.lastVersions
+		if prev == nil || p.lastKey != ts.Parents() {
+			parent, err := p.node.ChainGetTipSet(ctx, ts.Parents())
+			if err != nil {
+				return nil, nil, xerrors.Errorf("get parent tipset: %w", err)
+			}
+			prev, err = p.versions(ctx, parent.Height(), parent.ParentState())
+			if err != nil {
+				report.ErrorsDetected = xerrors.Errorf("get parent versions: %w", err)
+				return nil, report, nil
+			}
+		}
+		changed = *prev != *cur
+	}
+	p.lastKey, p.lastVersions = ts.Key(), cur
+
+	var out model.PersistableList
+	p.upgradesOnce.Do(func() {
+		out = append(out, p.upgrades())
+	})
+
+	if changed {
+		out = append(out, &chainmodel.NetworkVersion{
+			Height:           int64(ts.Height()),
+			StateRoot:        ts.ParentState().String(),
+			NetworkVersion:   cur.network,
+			ActorsVersion:    cur.actors,
+			StateTreeVersion: cur.stateTree,
+		})
+	}
+
+	return out, report, nil
+}
+
+type versions struct {
+	network   int64
+	actors    int64
+	stateTree int64
+}
+
+// versions returns the versions in effect at height, where root is the parent state of the tipset at that height.
+func (p *Task) versions(ctx context.Context, height abi.ChainEpoch, root cid.Cid) (*versions, error) {
+	nv := p.network.Version(ctx, height)
+
+	stv, err := stateTreeVersion(ctx, p.node, root)
+	if err != nil {
+		return nil, err
+	}
+
+	return &versions{
+		network:   int64(nv),
+		actors:    int64(actors.VersionForNetwork(nv)),
+		stateTree: int64(stv),
+	}, nil
+}
+
+func (p *Task) upgrades() chainmodel.NetworkUpgradeList {
+	var out chainmodel.NetworkUpgradeList
+	for _, u := range p.network.Upgrades() {
+		out = append(out, &chainmodel.NetworkUpgrade{
+			Height:         int64(u.Height),
+			NetworkVersion: int64(u.Network),
+			ActorsVersion:  int64(actors.VersionForNetwork(u.Network)),
+		})
+	}
+	return out
+}
+
+// stateTreeVersion returns the version of the state tree with the given root.
+func stateTreeVersion(ctx context.Context, node lens.StoreAPI, root cid.Cid) (types.StateTreeVersion, error) {
+	var raw cbg.Deferred
+	if err := node.Store().Get(ctx, root, &raw); err != nil {
+		return 0, xerrors.Errorf("load state root %s: %w", root, err)
+	}
+
+	var sr types.StateRoot
+	if err := sr.UnmarshalCBOR(bytes.NewReader(raw.Raw)); err != nil {
+		// the root is not a versioned root, which was introduced with state tree version 1
+		return types.StateTreeVersion0, nil
+	}
+	return sr.Version, nil
+}
@@ -0,0 +1,43 @@
+package networkversion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/network"
+	"github.com/filecoin-project/lotus/chain/stmgr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/lily/lens/util"
+	chainmodel "github.com/filecoin-project/lily/model/chain"
+)
+
+func TestUpgrades(t *testing.T) {
+	task := &Task{
+		network: util.NewNetwork(stmgr.UpgradeSchedule{
+			{Height: 10, Network: network.Version1},
+			{Height: 20, Network: network.Version4},
+			{Height: 30, Network: network.Version4}, // an upgrade keeping the network version, such as Refuel
+		}, network.Version4),
+	}
+
+	upgrades := task.upgrades()
+	assert.Equal(t, chainmodel.NetworkUpgradeList{
+		{Height: 10, NetworkVersion: 1, ActorsVersion: 0},
+		{Height: 20, NetworkVersion: 4, ActorsVersion: 2},
+		{Height: 30, NetworkVersion: 4, ActorsVersion: 2},
+	}, upgrades)
+
+	// upgrades sharing a network version are distinct rows
+	keys := map[[2]int64]bool{}
+	for _, u := range upgrades {
+		key := [2]int64{u.Height, u.NetworkVersion}
+		assert.False(t, keys[key], "duplicate key %v", key)
+		keys[key] = true
+	}
+
+	assert.Equal(t, network.Version0, task.network.Version(context.Background(), 10))
+	assert.Equal(t, network.Version1, task.network.Version(context.Background(), 11))
+	assert.Equal(t, network.Version4, task.network.Version(context.Background(), 21))
+	assert.Equal(t, network.Version4, task.network.Version(context.Background(), 31))
+}
@@ -0,0 +1,203 @@
+// Package nonces provides a task for tracking the nonces of the messages sent by each sender
+package nonces
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/ipfs/go-cid"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/label"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lily/chain/actors/policy"
+	"github.com/filecoin-project/lily/lens"
+	"github.com/filecoin-project/lily/model"
+	"github.com/filecoin-project/lily/model/derived"
+	visormodel "github.com/filecoin-project/lily/model/visor"
+	"github.com/filecoin-project/lily/tasks/messages"
+)
+
+type senderNonce struct {
+	sender address.Address
+	nonce  uint64
+}
+
+// inclusion records the message that was executed for a sender and nonce and the tipset it was included in.
+type inclusion struct {
+	cid    cid.Cid
+	height abi.ChainEpoch
+	tsk    types.TipSetKey
+}
+
+type Task struct {
+	node lens.API
+
+	mu sync.Mutex
+	// seen holds the message executed for each sender and nonce in recently processed tipsets. Since a nonce can only
+	// be used once on a chain, a different message executed for the same sender and nonce in another tipset means the
+	// earlier tipset was reverted. It is held in memory by each instance of the task, so it is lost when the job
+	// restarts and is not shared between jobs.
+	seen map[senderNonce]inclusion
+}
+
+func NewTask(node lens.API) *Task {
+	return &Task{
+		node: node,
+		seen: map[senderNonce]inclusion{},
+	}
+}
+
+// ProcessMessages summarizes the nonces of the messages executed for each sender in pts and records messages that
+// replaced a message with the same sender and nonce in a reverted tipset. Senders are identified by their ID address.
+//
+// Replacement detection is best effort: replacements are only detected between tipsets processed by the same task
+// within chain finality of each other. Only watches see tipsets that are later reverted, so walks never record
+// replacements.
+func (p *Task) ProcessMessages(ctx context.Context, ts *types.TipSet, pts *types.TipSet, emsgs []*lens.ExecutedMessage, blkMsgs []*lens.BlockMessages) (model.Persistable, *visormodel.ProcessingReport, error) {
+	ctx, span := global.Tracer("").Start(ctx, "ProcessNonces")
+	if span.IsRecording() {
+		span.SetAttributes(label.String("tipset", ts.String()), label.Int64("height", int64(ts.Height())))
+	}
+	defer span.End()
+
+	report := &visormodel.ProcessingReport{
+		Height:    int64(pts.Height()),
+		StateRoot: pts.ParentState().String(),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prune(pts.Height())
+
+	var (
+		senders        []address.Address
+		nonces         = map[address.Address][]uint64{}
+		firstMsg       = map[address.Address]cid.Cid{}
+		replacements   = derived.NonceReplacementList{}
+		seen           = map[cid.Cid]bool{}
+		ids            = map[address.Address]address.Address{}
+		errorsDetected []*messages.MessageError
+	)
+	for _, m := range emsgs {
+		select {
+		case <-ctx.Done():
+			return nil, nil, xerrors.Errorf("context done: %w", ctx.Err())
+		default:
+		}
+
+		if seen[m.Cid] {
+			continue
+		}
+		seen[m.Cid] = true
+
+		// a sender may use its ID address or its robust address so resolve it to keep its nonces in one sequence
+		from, ok := ids[m.Message.From]
+		if !ok {
+			from = m.Message.From
+			if from.Protocol() != address.ID {
+				id, err := p.node.StateLookupID(ctx, from, pts.Key())
+				if err != nil {
+					errorsDetected = append(errorsDetected, &messages.MessageError{
+						Cid:   m.Cid,
+						Error: xerrors.Errorf("failed to resolve sender %s: %w", from, err).Error(),
+					})
+				} else {
+					from = id
+				}
+			}
+			ids[m.Message.From] = from
+		}
+
+		if _, ok := nonces[from]; !ok {
+			senders = append(senders, from)
+			firstMsg[from] = m.Cid
+		}
+		nonces[from] = append(nonces[from], m.Message.Nonce)
+
+		key := senderNonce{sender: from, nonce: m.Message.Nonce}
+		if prev, ok := p.seen[key]; ok && prev.cid != m.Cid && prev.tsk != pts.Key() {
+			replacements = append(replacements, &derived.NonceReplacement{
+				Height:         int64(pts.Height()),
+				StateRoot:      pts.ParentState().String(),
+				Sender:         from.String(),
+				Nonce:          m.Message.Nonce,
+				Message:        m.Cid.String(),
+				ReplacedCid:    prev.cid.String(),
+				ReplacedHeight: int64(prev.height),
+				ReplacedTipSet: prev.tsk.String(),
+			})
+		}
+		// a message that failed because another message with the same nonce was executed first in the tipset does
+		// not use the nonce
+		if prev, ok := p.seen[key]; !ok || prev.tsk != pts.Key() || m.Receipt.ExitCode.IsSuccess() {
+			p.seen[key] = inclusion{cid: m.Cid, height: pts.Height(), tsk: pts.Key()}
+		}
+	}
+
+	out := make(derived.SenderNoncesList, 0, len(senders))
+	for _, from := range senders {
+		sn := summarize(nonces[from])
+		sn.Height = int64(pts.Height())
+		sn.StateRoot = pts.ParentState().String()
+		sn.Sender = from.String()
+
+		act, err := p.node.StateGetActor(ctx, from, pts.Key())
+		if err != nil {
+			errorsDetected = append(errorsDetected, &messages.MessageError{
+				Cid:   firstMsg[from],
+				Error: xerrors.Errorf("failed to load sender %s: %w", from, err).Error(),
+			})
+		} else {
+			sn.ExpectedNonce = act.Nonce
+			sn.Gap = int64(sn.FirstNonce) - int64(act.Nonce)
+		}
+		out = append(out, sn)
+	}
+
+	if len(errorsDetected) != 0 {
+		report.ErrorsDetected = errorsDetected
+	}
+
+	return model.PersistableList{out, replacements}, report, nil
+}
+
+// summarize returns the nonce summary of a sender's messages in the order they were executed.
+func summarize(nonces []uint64) *derived.SenderNonces {
+	sorted := make([]uint64, len(nonces))
+	copy(sorted, nonces)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	distinct := 0
+	for i := range sorted {
+		if i == 0 || sorted[i] != sorted[i-1] {
+			distinct++
+		}
+	}
+
+	first, last := sorted[0], sorted[len(sorted)-1]
+	return &derived.SenderNonces{
+		MessageCount:  int64(len(nonces)),
+		FirstNonce:    first,
+		LastNonce:     last,
+		ExpectedNonce: first,
+		Contiguous:    last-first+1 == uint64(distinct),
+	}
+}
+
+// prune removes inclusions that are further than chain finality from height since they can no longer be reverted.
+func (p *Task) prune(height abi.ChainEpoch) {
+	for key, inc := range p.seen {
+		d := inc.height - height
+		if d < 0 {
+			d = -d
+		}
+		if d > policy.ChainFinality {
+			delete(p.seen, key)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package nonces
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/lily/chain/actors/policy"
+)
+
+func TestSummarize(t *testing.T) {
+	sn := summarize([]uint64{7, 5, 6})
+	assert.EqualValues(t, 3, sn.MessageCount)
+	assert.EqualValues(t, 5, sn.FirstNonce)
+	assert.EqualValues(t, 7, sn.LastNonce)
+	assert.True(t, sn.Contiguous)
+
+	sn = summarize([]uint64{5, 8})
+	assert.EqualValues(t, 2, sn.MessageCount)
+	assert.False(t, sn.Contiguous)
+
+	// a duplicate nonce from conflicting messages in the same tipset does not break the sequence
+	sn = summarize([]uint64{5, 5, 6})
+	assert.EqualValues(t, 3, sn.MessageCount)
+	assert.True(t, sn.Contiguous)
+}
+
+func TestPrune(t *testing.T) {
+	addr, err := address.NewIDAddress(1000)
+	assert.NoError(t, err)
+
+	task := NewTask(nil)
+	task.seen[senderNonce{sender: addr, nonce: 1}] = inclusion{height: 10, tsk: types.EmptyTSK}
+	task.seen[senderNonce{sender: addr, nonce: 2}] = inclusion{height: 10 + policy.ChainFinality, tsk: types.EmptyTSK}
+
+	task.prune(11 + policy.ChainFinality)
+	assert.Len(t, task.seen, 1)
+	assert.Contains(t, task.seen, senderNonce{sender: addr, nonce: 2})
+}
@@ -33,10 +33,12 @@ type ChainVisRefresher struct {
 	refreshRate time.Duration
 }
 
-// Run starts regularly refreshing until context is done or an error occurs
+// Run starts regularly refreshing until context is done or an error occurs. With a zero refreshRate the views are
+// refreshed once, which suits jobs run on a schedule.
 func (r *ChainVisRefresher) Run(ctx context.Context) error {
 	if r.refreshRate == 0 {
-		return nil
+		_, err := r.refreshView(ctx)
+		return err
 	}
 	return wait.RepeatUntil(ctx, r.refreshRate, r.refreshView)
 }